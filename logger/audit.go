@@ -0,0 +1,240 @@
+package Logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// prev of the first record of an audit log
+var auditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// The hash member closing every audit record
+var auditHashPattern = regexp.MustCompile(`,"hash":"([0-9a-f]{64})"}$`)
+
+// Options of NewAuditLogger, the zero value is usable
+type AuditLoggerOptions struct {
+	Path       string // defaults to ./audit.log
+	BufferSize int32  // size of the channel, defaults to Logbuffersize
+}
+
+// A JSON line of an audit log. hash is the SHA-256 of the line up to (not including) the hash member, prev is the hash
+// of the previous line, so changing, removing or reordering lines breaks the chain.
+type auditRecord struct {
+	Seq  uint64 `json:"seq"`
+	Prev string `json:"prev"`
+	entryRecord
+}
+
+// Returns a started logger appending hash chained JSON records to an audit log, continuing the chain of an existing file.
+// Every record is written and fsynced on its own and the channel always blocks when it is full, nothing is dropped.
+// SetFsyncPolicy and SetOverflowPolicy can't change that.
+func NewAuditLogger(opts AuditLoggerOptions) (*AuditLoggerImpl, error) {
+	lgr := &AuditLoggerImpl{}
+	lgr.filepath = opts.Path
+	if err := lgr.setup(bufferSize(opts.BufferSize)); err != nil {
+		return nil, err
+	}
+	lgr.start(lgr.run)
+	return lgr, nil
+}
+
+// The path can be set with SetLogFilePath, LOGFILE_GO_LOGGER is not consulted
+func (lgr *AuditLoggerImpl) init() {
+	lgr.filepath = lgr.initfilepath
+	if err := lgr.setup(Logbuffersize); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (lgr *AuditLoggerImpl) setup(size int32) error {
+	if lgr.filepath == "" {
+		lgr.filepath = "./audit.log"
+	}
+	lgr.sink = lgr
+	lgr.FileLoggerImpl.SetFsyncPolicy(FsyncAlways, 0)
+	lgr.FileLoggerImpl.SetOverflowPolicy(OverflowBlock, 0)
+	lgr.initQueue(size)
+	if err := lgr.open(); err != nil {
+		return err
+	}
+	return lgr.resume()
+}
+
+// Does nothing, every record of an audit log is fsynced on its own
+func (lgr *AuditLoggerImpl) SetFsyncPolicy(policy FsyncPolicy, interval time.Duration) {}
+
+// Does nothing, an audit log always blocks when its channel is full so no record is dropped
+func (lgr *AuditLoggerImpl) SetOverflowPolicy(policy OverflowPolicy, sampleRate int) {}
+
+// Picks up seq and hash of the last record in the file. A last record cut short by a crash (it was never synced
+// as a whole) is cut off the file and kept in a warning that becomes the next record of the chain.
+func (lgr *AuditLoggerImpl) resume() error {
+	lgr.prevHash = auditGenesisHash
+	last, partial, err := lastLine(lgr.logFile)
+	if err != nil {
+		return err
+	}
+	if len(partial) > 0 {
+		info, err := lgr.logFile.Stat()
+		if err != nil {
+			return err
+		}
+		if err := lgr.logFile.Truncate(info.Size() - int64(len(partial))); err != nil {
+			return fmt.Errorf("Error continuing audit log %s: %w", lgr.filepath, err)
+		}
+		lgr.logEntry(&Entry{Time: lgr.now(), Level: LevelWarn, Message: "discarded a record cut short by a crash", Fields: []Field{{Key: "partial", Value: string(partial)}}})
+	}
+	if len(last) == 0 {
+		return nil
+	}
+	m := auditHashPattern.FindSubmatch(last)
+	var rec auditRecord
+	if m == nil || json.Unmarshal(last, &rec) != nil {
+		return fmt.Errorf("Error continuing audit log %s: the last record is damaged", lgr.filepath)
+	}
+	lgr.prevHash = string(m[1])
+	lgr.seq = rec.Seq
+	return nil
+}
+
+// The last non empty complete line of f without the newline and whatever follows its last newline (a line cut short),
+// read from the end so large files are cheap
+func lastLine(f *os.File) (last []byte, partial []byte, err error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		n := min(int64(4096), offset)
+		offset -= n
+		chunk := make([]byte, n)
+		if _, err := f.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		tail = append(chunk, tail...)
+		end := bytes.LastIndexByte(tail, '\n')
+		if end < 0 {
+			continue
+		}
+		trimmed := bytes.TrimRight(tail[:end+1], "\n")
+		if start := bytes.LastIndexByte(trimmed, '\n'); start >= 0 {
+			return trimmed[start+1:], tail[end+1:], nil
+		}
+		if offset == 0 {
+			return trimmed, tail[end+1:], nil
+		}
+	}
+	return nil, tail, nil
+}
+
+func (logger *AuditLoggerImpl) StartLogger() {
+	logger.start(logger.run)
+}
+
+// Every record is written and synced on its own
+func (logger *AuditLoggerImpl) run() {
+	for msg := range logger.messages {
+		logger.write(logger.chain(msg))
+		logger.sync()
+		logger.markProcessed(1)
+	}
+}
+
+// The next line of the chain including the newline
+func (logger *AuditLoggerImpl) chain(e *Entry) []byte {
+	logger.seq++
+	body, err := json.Marshal(auditRecord{Seq: logger.seq, Prev: logger.prevHash, entryRecord: e.record()})
+	if err != nil {
+		body, _ = json.Marshal(auditRecord{Seq: logger.seq, Prev: logger.prevHash, entryRecord: entryRecord{Time: e.Time, Level: e.Level.String(), Message: e.text()}})
+	}
+	sum := sha256.Sum256(body[:len(body)-1])
+	logger.prevHash = hex.EncodeToString(sum[:])
+	line := append(body[:len(body)-1], `,"hash":"`...)
+	line = append(line, logger.prevHash...)
+	return append(line, "\"}\n"...)
+}
+
+// The record has to go through the queue to keep the chain intact
+func (logger *AuditLoggerImpl) writeCrash(ctx context.Context, e *Entry) {
+	logger.log(e)
+	logger.flush(ctx)
+}
+
+// Stops the logger, waits until the queued records are written and closes the file
+func (logger *AuditLoggerImpl) Close(ctx context.Context) error {
+	return logger.close(ctx, logger.run, func() error {
+		logger.mutex.Lock()
+		defer logger.mutex.Unlock()
+		return logger.logFile.Close()
+	})
+}
+
+// Reported by VerifyAuditLog for the first record that doesn't fit in the chain
+type AuditVerifyError struct {
+	Line    int // 1 based
+	Reason  string
+	Partial bool // the last record was cut short (a crash while writing it), the records before it are intact
+}
+
+func (e *AuditVerifyError) Error() string {
+	return fmt.Sprintf("audit log broken at line %d: %s", e.Line, e.Reason)
+}
+
+// Walks an audit log and returns an *AuditVerifyError for the first record that was modified, removed, inserted or
+// reordered, nil if the chain is intact. A last record cut short by a crash is reported with Partial set, the next
+// NewAuditLogger on the file repairs it. Whole records cut off from the end of the file can't be detected.
+func VerifyAuditLog(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	prev, seq := auditGenesisHash, uint64(0)
+	for line := 1; ; line++ {
+		text, err := r.ReadBytes('\n')
+		if err == io.EOF && len(text) == 0 {
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if !bytes.HasSuffix(text, []byte("\n")) {
+			return &AuditVerifyError{Line: line, Reason: "last record is cut short", Partial: true}
+		}
+		text = text[:len(text)-1]
+
+		m := auditHashPattern.FindSubmatchIndex(text)
+		if m == nil {
+			return &AuditVerifyError{Line: line, Reason: "record has no hash"}
+		}
+		hash := string(text[m[2]:m[3]])
+		sum := sha256.Sum256(text[:m[0]])
+		if hex.EncodeToString(sum[:]) != hash {
+			return &AuditVerifyError{Line: line, Reason: "hash doesn't match the record"}
+		}
+		var rec auditRecord
+		if err := json.Unmarshal(text, &rec); err != nil {
+			return &AuditVerifyError{Line: line, Reason: "record isn't valid JSON: " + err.Error()}
+		}
+		if rec.Prev != prev {
+			return &AuditVerifyError{Line: line, Reason: "prev doesn't match the hash of the previous record"}
+		}
+		if rec.Seq != seq+1 {
+			return &AuditVerifyError{Line: line, Reason: fmt.Sprintf("expected seq %d, got %d", seq+1, rec.Seq)}
+		}
+		prev, seq = hash, rec.Seq
+	}
+}
@@ -0,0 +1,98 @@
+package Logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func writeAuditLog(t *testing.T, path string, messages ...string) {
+	t.Helper()
+	lgr, err := NewAuditLogger(AuditLoggerOptions{Path: path})
+	Testing.AssertNotError(t, err)
+	for _, msg := range messages {
+		lgr.WriteRequest(msg, "admin")
+	}
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+}
+
+func auditVerifyLine(err error) int {
+	var verr *AuditVerifyError
+	if errors.As(err, &verr) {
+		return verr.Line
+	}
+	return 0
+}
+
+func Test_auditLogChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeAuditLog(t, path, "user created", "role granted")
+	// A new logger continues the chain of the file
+	writeAuditLog(t, path, "user deleted")
+	Testing.AssertNotError(t, VerifyAuditLog(path))
+
+	content, _ := os.ReadFile(path)
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	Testing.AssertTrue(t, bytes.HasPrefix(lines[2], []byte(`{"seq":3,"prev":"`)))
+	Testing.AssertTrue(t, bytes.Contains(lines[2], []byte(`"request_id":"admin","message":"user deleted"`)))
+
+	tampered := bytes.Replace(content, []byte("role granted"), []byte("role revoked"), 1)
+	os.WriteFile(path, tampered, 0660)
+	Testing.AssertEqual(t, 2, auditVerifyLine(VerifyAuditLog(path)))
+
+	// Removing a record breaks the link of the next one
+	os.WriteFile(path, append(append([]byte{}, lines[0]...), lines[2]...), 0660)
+	err := VerifyAuditLog(path)
+	Testing.AssertEqual(t, 2, auditVerifyLine(err))
+	Testing.AssertEqual(t, "audit log broken at line 2: prev doesn't match the hash of the previous record", err.Error())
+
+	// A damaged last record that isn't cut short can't be continued
+	os.WriteFile(path, bytes.Replace(content, []byte(`"hash":"`), []byte(`"hash":"x`), 3), 0660)
+	_, err = NewAuditLogger(AuditLoggerOptions{Path: path})
+	Testing.AssertError(t, err)
+}
+
+func Test_auditLogPartialRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeAuditLog(t, path, "user created", "role granted")
+	content, _ := os.ReadFile(path)
+	// A crash while the 2nd record was written
+	cut := bytes.IndexByte(content, '\n') + 20
+	os.WriteFile(path, content[:cut], 0660)
+
+	err := VerifyAuditLog(path)
+	var verr *AuditVerifyError
+	Testing.AssertTrue(t, errors.As(err, &verr))
+	Testing.AssertEqual(t, 2, verr.Line)
+	Testing.AssertTrue(t, verr.Partial)
+
+	// The logger cuts it off and keeps it in a warning, the chain stays intact
+	writeAuditLog(t, path, "user deleted")
+	Testing.AssertNotError(t, VerifyAuditLog(path))
+	repaired, _ := os.ReadFile(path)
+	lines := bytes.Split(bytes.TrimSuffix(repaired, []byte("\n")), []byte("\n"))
+	Testing.AssertEqual(t, 3, len(lines))
+	Testing.AssertTrue(t, bytes.HasPrefix(lines[1], []byte(`{"seq":2,`)))
+	Testing.AssertTrue(t, bytes.Contains(lines[1], []byte(`"level":"warn","message":"discarded a record cut short by a crash","fields":{"partial":"`)))
+	Testing.AssertTrue(t, bytes.Contains(lines[2], []byte(`"seq":3,`)))
+
+	// Only a partial record
+	os.WriteFile(path, content[:10], 0660)
+	writeAuditLog(t, path)
+	Testing.AssertNotError(t, VerifyAuditLog(path))
+}
+
+func Test_auditLogPoliciesCannotBeWeakened(t *testing.T) {
+	lgr, err := NewAuditLogger(AuditLoggerOptions{Path: filepath.Join(t.TempDir(), "audit.log")})
+	Testing.AssertNotError(t, err)
+	defer lgr.Close(context.Background())
+	lgr.SetFsyncPolicy(FsyncNever, 0)
+	lgr.SetOverflowPolicy(OverflowDropNewest, 0)
+	Testing.AssertEqual(t, FsyncAlways, lgr.fsyncPolicy)
+	Testing.AssertEqual(t, OverflowBlock, lgr.overflow)
+}
@@ -0,0 +1,82 @@
+package Logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Frames between the code calling a Logger method and newEntry: Write* / *Ctx / Span methods / LogResult etc. -> emit / emitCtx -> newEntry
+const callerSkip = 3
+
+// Upper bound of frames captured for a stack trace
+const maxStackDepth = 32
+
+// Where a message was logged from
+type Caller struct {
+	File     string // base name of the file
+	Function string // package qualified function name without the import path
+	Line     int
+}
+
+// Same format as the assert package: file.go/pkg.Func():line
+func (c *Caller) String() string {
+	return c.File + "/" + c.Function + "():" + strconv.Itoa(c.Line)
+}
+
+// skip is relative to the caller of callerAt
+func callerAt(skip int) *Caller {
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return nil
+	}
+	return &Caller{File: filepath.Base(file), Function: shortFuncName(runtime.FuncForPC(pc)), Line: line}
+}
+
+// The caller recorded as a program counter, e.g. slog.Record.PC, nil for 0
+func callerAtPC(pc uintptr) *Caller {
+	if pc == 0 {
+		return nil
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function == "" && frame.File == "" {
+		return nil
+	}
+	return &Caller{File: filepath.Base(frame.File), Function: shortName(frame.Function), Line: frame.Line}
+}
+
+// One frame per line: function then file:line indented with a tab, skip is relative to the caller of stackAt
+func stackAt(skip int) string {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		b.WriteByte('\n')
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+func shortFuncName(fn *runtime.Func) string {
+	if fn == nil {
+		return "?"
+	}
+	return shortName(fn.Name())
+}
+
+func shortName(name string) string {
+	if name == "" {
+		return "?"
+	}
+	return name[strings.LastIndex(name, "/")+1:]
+}
@@ -0,0 +1,32 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_callerCapture(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	lgr.SetCallerCapture(true)
+	lgr.SetStackCapture(true)
+
+	_, _, line, _ := runtime.Caller(0)
+	lgr.WriteRequest("info", "uuid")
+	lgr.ErrorCtx(context.Background(), errors.New("some error"))
+	lgr.WriteErrMsgRequestDebug(errors.New("debug error"), "debug", "uuid")
+
+	lines := readLines(t, lgr)
+	caller := "caller_test.go/logger.Test_callerCapture():"
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : uuid : info caller="+caller+strconv.Itoa(line+1)))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], " : Error: some error caller="+caller+strconv.Itoa(line+2)))
+	// Stack trace of the error, starting at the test function
+	Testing.AssertEqual(t, "\tgithub.com/lbatuska/goutils/logger.Test_callerCapture", lines[2])
+	Testing.AssertTrue(t, strings.HasSuffix(lines[3], "/caller_test.go:"+strconv.Itoa(line+2)))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[len(lines)-1], " : uuid debug: Error: debug error caller="+caller+strconv.Itoa(line+3)))
+}
@@ -0,0 +1,76 @@
+package Logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Source of the timestamps of a logger, see SetClock
+type Clock interface {
+	Now() time.Time
+}
+
+// The system clock
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// A clock that only moves when it is told to, for tests asserting on exact log lines
+type ManualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = now
+}
+
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Timestamps come from clock instead of time.Now, nil restores the system clock. Call it before the logger is used.
+func (c *core) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+// Timestamps are converted to loc, e.g. time.UTC, nil keeps the location of the clock (Local for the system clock).
+// Call it before the logger is used.
+func (c *core) SetLocation(loc *time.Location) {
+	c.location = loc
+}
+
+// Layout of the timestamps of text output, e.g. time.RFC3339Nano. Empty means the default of the layout: time.UnixDate
+// for LayoutFull, 15:04:05.000 for LayoutCompact. JSON output always uses RFC 3339. Call it before the logger is used.
+func (c *core) SetTimeFormat(format string) {
+	c.timeFormat = format
+}
+
+func (c *core) now() time.Time {
+	var now time.Time
+	if c.clock == nil {
+		now = time.Now()
+	} else {
+		now = c.clock.Now()
+	}
+	if c.location != nil {
+		now = now.In(c.location)
+	}
+	return now
+}
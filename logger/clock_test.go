@@ -0,0 +1,79 @@
+package Logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_manualClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.FixedZone("CEST", 2*60*60)))
+	lgr := NewMemoryLogger(10)
+	lgr.SetClock(clock)
+	lgr.Write("first")
+	clock.Advance(time.Second)
+	span := lgr.Span("job")
+	clock.Advance(1500 * time.Millisecond)
+	span.End()
+
+	entries := lgr.Entries()
+	Testing.AssertEqual(t, "Wed May  1 12:30:00 CEST 2024 : first\n", entries[0].line())
+	Testing.AssertEqual(t, any(1500*time.Millisecond), spanField(entries[2], "duration"))
+}
+
+func Test_timeFormatAndLocation(t *testing.T) {
+	var out bytes.Buffer
+	lgr := &ConsoleLoggerImpl{out: &out}
+	lgr.init()
+	lgr.SetClock(NewManualClock(time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.FixedZone("CEST", 2*60*60))))
+	lgr.SetLocation(time.UTC)
+	lgr.SetTimeFormat(time.RFC3339Nano)
+	lgr.WriteRequest("hello", "uuid-1")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertEqual(t, "2024-05-01T10:30:00.123456789Z : uuid-1 : hello\n", out.String())
+
+	cfg := DefaultLoggerConfig()
+	Testing.AssertNotError(t, cfg.set("LOG_TIME_FORMAT", "RFC3339Nano"))
+	Testing.AssertEqual(t, time.RFC3339Nano, cfg.TimeFormat)
+	Testing.AssertNotError(t, cfg.set("LOG_TIME_FORMAT", "2006-01-02"))
+	Testing.AssertEqual(t, "2006-01-02", cfg.TimeFormat)
+}
+
+func Test_clockStampsAccessLogAndDropReport(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60)))
+
+	memory := NewMemoryLogger(10)
+	memory.SetClock(clock)
+	memory.SetLocation(time.UTC)
+	handler := AccessLog(memory, AccessLogOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	access := memory.Entries()[0]
+	Testing.AssertEqual(t, "Wed May  1 10:30:00 UTC 2024", access.Time.Format(time.UnixDate))
+	Testing.AssertTrue(t, strings.Contains(access.Message, "[01/May/2024:10:30:00 +0000]"))
+
+	defer func(interval time.Duration) { DropReportInterval = interval }(DropReportInterval)
+	DropReportInterval = time.Millisecond
+	var out bytes.Buffer
+	lgr := &ConsoleLoggerImpl{out: &out}
+	lgr.sink = lgr
+	lgr.SetOverflowPolicy(OverflowDropNewest, 0)
+	lgr.initQueue(1)
+	lgr.SetClock(clock)
+	lgr.SetLocation(time.UTC)
+	lgr.Write("kept")
+	lgr.Write("dropped")
+	lgr.start(lgr.run)
+	// Waits for the report written by the writer goroutine
+	deadline := time.Now().Add(5 * time.Second)
+	for lgr.Stats().WriteLatency.Count < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertEqual(t, "Wed May  1 10:30:00 UTC 2024 : kept\nWed May  1 10:30:00 UTC 2024 : 1 messages dropped\n", out.String())
+}
@@ -0,0 +1,30 @@
+// Checks the hash chain of audit logs written by Logger.AuditLoggerImpl.
+//
+//	verifyaudit audit.log [more.log...]
+//
+// Exits with 1 if a file is broken or can't be read, the first broken record of each file is reported.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	Logger "github.com/lbatuska/goutils/logger"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: verifyaudit FILE...")
+		os.Exit(2)
+	}
+	status := 0
+	for _, path := range os.Args[1:] {
+		if err := Logger.VerifyAuditLog(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			status = 1
+			continue
+		}
+		fmt.Printf("%s: ok\n", path)
+	}
+	os.Exit(status)
+}
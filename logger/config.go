@@ -0,0 +1,274 @@
+package Logger
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Describes the logger built by BuildLogger. Start from DefaultLoggerConfig and layer LoadFile and LoadEnv on top.
+// Every field can be set by the key named in its comment, the same keys are used in files and in the environment.
+type LoggerConfig struct {
+	Level         Level          // LOG_LEVEL: debug, info, warn or error
+	Format        ConsoleLayout  // LOG_FORMAT: full, compact or json
+	Color         ColorMode      // LOG_COLOR: auto, always or never
+	Console       bool           // LOG_CONSOLE: log to stdout
+	File          string         // LOG_FILE: log to this file
+	FsyncPolicy   FsyncPolicy    // LOG_FSYNC: always, interval, error or never
+	FsyncInterval time.Duration  // LOG_FSYNC_INTERVAL: e.g. 500ms
+	Syslog        string         // LOG_SYSLOG: network://address, e.g. udp://localhost:514 or unixgram:///dev/log
+	Journald      bool           // LOG_JOURNALD: log to the local journal
+	HTTPURL       string         // LOG_HTTP_URL: ship to this collector
+	HTTPSpool     string         // LOG_HTTP_SPOOL: spool file of the HTTP sink
+	BufferSize    int32          // LOG_BUFFER: channel size of each buffered sink
+	Overflow      OverflowPolicy // LOG_OVERFLOW: block, drop-newest, drop-oldest or sample
+	SampleRate    int            // LOG_SAMPLE_RATE: used by the sample overflow policy
+	Caller        bool           // LOG_CALLER: record the caller of every message
+	Stack         bool           // LOG_STACK: record a stack trace for errors
+	Redact        bool           // LOG_REDACT: redact with DefaultRedactor
+	TimeFormat    string         // LOG_TIME_FORMAT: unixdate, rfc3339, rfc3339nano or a Go time layout
+	UTC           bool           // LOG_UTC: timestamps in UTC instead of local time
+}
+
+// The keys understood by LoadEnv and LoadFile
+var configKeys = []string{
+	"LOG_LEVEL", "LOG_FORMAT", "LOG_COLOR", "LOG_CONSOLE", "LOG_FILE", "LOG_FSYNC", "LOG_FSYNC_INTERVAL",
+	"LOG_SYSLOG", "LOG_JOURNALD", "LOG_HTTP_URL", "LOG_HTTP_SPOOL", "LOG_BUFFER", "LOG_OVERFLOW",
+	"LOG_SAMPLE_RATE", "LOG_CALLER", "LOG_STACK", "LOG_REDACT", "LOG_TIME_FORMAT", "LOG_UTC",
+}
+
+// Names accepted by LOG_TIME_FORMAT besides layouts
+var timeFormats = map[string]string{
+	"unixdate":    time.UnixDate,
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+}
+
+// Info level, console output in the full layout, colored on terminals
+func DefaultLoggerConfig() LoggerConfig {
+	return LoggerConfig{Level: LevelInfo, Console: true, BufferSize: Logbuffersize}
+}
+
+// Overrides the fields whose key is set in the environment, every invalid value is reported
+func (cfg *LoggerConfig) LoadEnv() error {
+	var errs []error
+	for _, key := range configKeys {
+		if value, ok := os.LookupEnv(key); ok {
+			errs = append(errs, cfg.set(key, value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Overrides the fields set in a file of KEY=value lines. Blank lines and lines starting with # are skipped,
+// values may be quoted. Unknown keys and invalid values are reported with their line number.
+func (cfg *LoggerConfig) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var errs []error
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s:%d: expected KEY=value", path, line))
+			continue
+		}
+		key, value = strings.ToUpper(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		if err := cfg.set(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", path, line, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (cfg *LoggerConfig) set(key string, value string) (err error) {
+	switch key {
+	case "LOG_LEVEL":
+		cfg.Level, err = ParseLevel(value)
+	case "LOG_FORMAT":
+		cfg.Format, err = parseOption(value, map[string]ConsoleLayout{"full": LayoutFull, "compact": LayoutCompact, "json": LayoutJSON})
+	case "LOG_COLOR":
+		cfg.Color, err = parseOption(value, map[string]ColorMode{"auto": ColorAuto, "always": ColorAlways, "never": ColorNever})
+	case "LOG_CONSOLE":
+		cfg.Console, err = strconv.ParseBool(value)
+	case "LOG_FILE":
+		cfg.File = value
+	case "LOG_FSYNC":
+		cfg.FsyncPolicy, err = parseOption(value, map[string]FsyncPolicy{"always": FsyncAlways, "interval": FsyncInterval, "error": FsyncOnError, "never": FsyncNever})
+	case "LOG_FSYNC_INTERVAL":
+		cfg.FsyncInterval, err = time.ParseDuration(value)
+	case "LOG_SYSLOG":
+		cfg.Syslog = value
+	case "LOG_JOURNALD":
+		cfg.Journald, err = strconv.ParseBool(value)
+	case "LOG_HTTP_URL":
+		cfg.HTTPURL = value
+	case "LOG_HTTP_SPOOL":
+		cfg.HTTPSpool = value
+	case "LOG_BUFFER":
+		var size int64
+		size, err = strconv.ParseInt(value, 10, 32)
+		cfg.BufferSize = int32(size)
+	case "LOG_OVERFLOW":
+		cfg.Overflow, err = parseOption(value, map[string]OverflowPolicy{"block": OverflowBlock, "drop-newest": OverflowDropNewest, "drop-oldest": OverflowDropOldest, "sample": OverflowSample})
+	case "LOG_SAMPLE_RATE":
+		cfg.SampleRate, err = strconv.Atoi(value)
+	case "LOG_CALLER":
+		cfg.Caller, err = strconv.ParseBool(value)
+	case "LOG_STACK":
+		cfg.Stack, err = strconv.ParseBool(value)
+	case "LOG_REDACT":
+		cfg.Redact, err = strconv.ParseBool(value)
+	case "LOG_TIME_FORMAT":
+		cfg.TimeFormat = value
+		if format, ok := timeFormats[strings.ToLower(value)]; ok {
+			cfg.TimeFormat = format
+		}
+	case "LOG_UTC":
+		cfg.UTC, err = strconv.ParseBool(value)
+	default:
+		return fmt.Errorf("unknown key %s", key)
+	}
+	if err != nil {
+		return fmt.Errorf("%s=%q: %w", key, value, err)
+	}
+	return nil
+}
+
+func parseOption[T any](value string, options map[string]T) (T, error) {
+	if option, ok := options[strings.ToLower(value)]; ok {
+		return option, nil
+	}
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var zero T
+	return zero, fmt.Errorf("expected one of %s", strings.Join(names, ", "))
+}
+
+// Reports every problem of the config at once
+func (cfg *LoggerConfig) Validate() error {
+	var errs []error
+	if cfg.Level < LevelDebug || cfg.Level > LevelError {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: invalid level %d", cfg.Level))
+	}
+	if cfg.Format < LayoutFull || cfg.Format > LayoutJSON {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT: invalid format %d", cfg.Format))
+	}
+	if cfg.BufferSize < 0 {
+		errs = append(errs, fmt.Errorf("LOG_BUFFER: must not be negative, got %d", cfg.BufferSize))
+	}
+	if cfg.FsyncInterval < 0 {
+		errs = append(errs, fmt.Errorf("LOG_FSYNC_INTERVAL: must not be negative, got %s", cfg.FsyncInterval))
+	}
+	if cfg.Overflow == OverflowSample && cfg.SampleRate < 2 {
+		errs = append(errs, fmt.Errorf("LOG_SAMPLE_RATE: must be at least 2 with the sample overflow policy, got %d", cfg.SampleRate))
+	}
+	if cfg.File != "" {
+		if info, err := os.Stat(filepath.Dir(cfg.File)); err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("LOG_FILE: directory of %s doesn't exist", cfg.File))
+		}
+	}
+	if cfg.Syslog != "" {
+		if _, _, err := parseSyslogAddress(cfg.Syslog); err != nil {
+			errs = append(errs, fmt.Errorf("LOG_SYSLOG: %w", err))
+		}
+	}
+	if cfg.HTTPURL != "" {
+		if u, err := url.Parse(cfg.HTTPURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("LOG_HTTP_URL: expected an http or https URL, got %q", cfg.HTTPURL))
+		}
+	} else if cfg.HTTPSpool != "" {
+		errs = append(errs, errors.New("LOG_HTTP_SPOOL: set without LOG_HTTP_URL"))
+	}
+	if !cfg.Console && cfg.File == "" && cfg.Syslog == "" && !cfg.Journald && cfg.HTTPURL == "" {
+		errs = append(errs, errors.New("no sink configured, set LOG_CONSOLE, LOG_FILE, LOG_SYSLOG, LOG_JOURNALD or LOG_HTTP_URL"))
+	}
+	return errors.Join(errs...)
+}
+
+func parseSyslogAddress(address string) (network string, addr string, err error) {
+	network, addr, ok := strings.Cut(address, "://")
+	if !ok || addr == "" {
+		return "", "", fmt.Errorf("expected network://address, got %q", address)
+	}
+	switch network {
+	case "udp", "tcp", "unix", "unixgram":
+		return network, addr, nil
+	}
+	return "", "", fmt.Errorf("unsupported network %q", network)
+}
+
+// Validates cfg and returns a started MultiLogger writing to every configured sink at cfg.Level.
+// The level is applied by the sinks only, the Debug methods still check DEBUG which is left to the caller.
+// Sinks created before a failure are closed.
+func BuildLogger(cfg LoggerConfig) (*MultiLogger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var sinks []MultiSink
+	add := func(l Logger) {
+		sinks = append(sinks, MultiSink{Logger: l, Level: cfg.Level})
+	}
+	if cfg.Console {
+		add(NewConsoleLogger(ConsoleLoggerOptions{BufferSize: cfg.BufferSize, Overflow: cfg.Overflow, SampleRate: cfg.SampleRate, Layout: cfg.Format, Color: cfg.Color, TimeFormat: cfg.TimeFormat}))
+	}
+	if cfg.File != "" {
+		file, err := NewFileLogger(FileLoggerOptions{Path: cfg.File, BufferSize: cfg.BufferSize, FsyncPolicy: cfg.FsyncPolicy, FsyncInterval: cfg.FsyncInterval, Overflow: cfg.Overflow, SampleRate: cfg.SampleRate, Layout: cfg.Format, TimeFormat: cfg.TimeFormat})
+		if err != nil {
+			NewMultiLogger(sinks...).Close(context.Background())
+			return nil, err
+		}
+		add(file)
+	}
+	if cfg.Syslog != "" {
+		network, address, _ := parseSyslogAddress(cfg.Syslog)
+		add(NewSyslogLogger(SyslogOptions{Network: network, Address: address, BufferSize: cfg.BufferSize, Overflow: cfg.Overflow, SampleRate: cfg.SampleRate}))
+	}
+	if cfg.Journald {
+		add(NewJournaldLogger(JournaldOptions{BufferSize: cfg.BufferSize, Overflow: cfg.Overflow, SampleRate: cfg.SampleRate}))
+	}
+	if cfg.HTTPURL != "" {
+		shipper, err := NewHTTPLogger(HTTPLoggerOptions{URL: cfg.HTTPURL, SpoolPath: cfg.HTTPSpool, BufferSize: cfg.BufferSize, Overflow: cfg.Overflow, SampleRate: cfg.SampleRate})
+		if err != nil {
+			NewMultiLogger(sinks...).Close(context.Background())
+			return nil, err
+		}
+		add(shipper)
+	}
+
+	lgr := NewMultiLogger(sinks...)
+	lgr.SetCallerCapture(cfg.Caller)
+	lgr.SetStackCapture(cfg.Stack)
+	if cfg.Redact {
+		lgr.SetRedactor(DefaultRedactor)
+	}
+	if cfg.UTC {
+		lgr.SetLocation(time.UTC)
+	}
+	return lgr, nil
+}
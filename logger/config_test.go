@@ -0,0 +1,95 @@
+package Logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_loggerConfigLayers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logger.conf")
+	os.WriteFile(path, []byte(`# defaults of the service
+LOG_LEVEL=warn
+log_format = "compact"
+LOG_FILE=`+filepath.Join(dir, "log")+`
+LOG_FSYNC=interval
+LOG_FSYNC_INTERVAL=250ms
+`), 0660)
+
+	cfg := DefaultLoggerConfig()
+	Testing.AssertNotError(t, cfg.LoadFile(path))
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("LOG_CONSOLE", "false")
+	Testing.AssertNotError(t, cfg.LoadEnv())
+
+	Testing.AssertEqual(t, LevelError, cfg.Level)
+	Testing.AssertEqual(t, LayoutCompact, cfg.Format)
+	Testing.AssertEqual(t, FsyncInterval, cfg.FsyncPolicy)
+	Testing.AssertEqual(t, 250*time.Millisecond, cfg.FsyncInterval)
+	Testing.AssertFalse(t, cfg.Console)
+	Testing.AssertNotError(t, cfg.Validate())
+}
+
+func Test_loggerConfigErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logger.conf")
+	os.WriteFile(path, []byte("LOG_LEVEL=loud\nLOG_COLOUR=always\nnonsense\nLOG_OVERFLOW=drop-oldest\n"), 0660)
+	cfg := DefaultLoggerConfig()
+	err := cfg.LoadFile(path)
+	Testing.AssertError(t, err)
+	Testing.AssertTrue(t, strings.Contains(err.Error(), `logger.conf:1: LOG_LEVEL="loud": unknown log level "loud"`))
+	Testing.AssertTrue(t, strings.Contains(err.Error(), "logger.conf:2: unknown key LOG_COLOUR"))
+	Testing.AssertTrue(t, strings.Contains(err.Error(), "logger.conf:3: expected KEY=value"))
+	// Valid lines are applied regardless
+	Testing.AssertEqual(t, OverflowDropOldest, cfg.Overflow)
+
+	t.Setenv("LOG_FORMAT", "xml")
+	err = cfg.LoadEnv()
+	Testing.AssertTrue(t, strings.Contains(err.Error(), `LOG_FORMAT="xml": expected one of compact, full, json`))
+
+	cfg = LoggerConfig{Overflow: OverflowSample, File: "/does/not/exist/log", Syslog: "carrier-pigeon://home", HTTPSpool: "spool"}
+	err = cfg.Validate()
+	Testing.AssertError(t, err)
+	for _, key := range []string{"LOG_SAMPLE_RATE", "LOG_FILE", "LOG_SYSLOG", "LOG_HTTP_SPOOL"} {
+		Testing.AssertTrue(t, strings.Contains(err.Error(), key))
+	}
+	Testing.AssertTrue(t, strings.Contains((&LoggerConfig{}).Validate().Error(), "no sink configured"))
+	_, err = BuildLogger(LoggerConfig{})
+	Testing.AssertError(t, err)
+}
+
+func Test_buildLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	cfg := DefaultLoggerConfig()
+	cfg.Console = false
+	cfg.File = path
+	cfg.Format = LayoutJSON
+	cfg.Level = LevelWarn
+	lgr, err := BuildLogger(cfg)
+	Testing.AssertNotError(t, err)
+	lgr.Write("filtered")
+	lgr.WarnCtx(context.Background(), "kept")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	content, _ := os.ReadFile(path)
+	Testing.AssertEqual(t, 1, strings.Count(string(content), "\n"))
+	Testing.AssertTrue(t, strings.Contains(string(content), `"level":"warn","message":"kept"`))
+}
+
+func Test_buildLoggerLeavesDEBUG(t *testing.T) {
+	defer func(debug bool) { DEBUG = debug }(DEBUG)
+	DEBUG = false
+	cfg := DefaultLoggerConfig()
+	cfg.Console = false
+	cfg.File = filepath.Join(t.TempDir(), "log")
+	cfg.Level = LevelDebug
+	lgr, err := BuildLogger(cfg)
+	Testing.AssertNotError(t, err)
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertFalse(t, DEBUG)
+}
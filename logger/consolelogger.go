@@ -1,85 +1,197 @@
 package Logger
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 )
 
+const compactTimeFormat = "15:04:05.000"
+
+const (
+	colorNone   = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorGray   = "\033[90m"
+)
+
+// ANSI escape sequences for the parts of a message, empty strings leave the part uncolored
+type palette struct {
+	dim       string
+	requestID string
+	labels    [4]string // by Level
+	messages  [4]string // by Level
+}
+
+var (
+	noColors   = &palette{}
+	ansiColors = &palette{
+		dim:       colorGray,
+		requestID: colorCyan,
+		labels:    [4]string{colorGray, colorGreen, colorYellow, colorRed},
+		messages:  [4]string{colorGray, "", colorYellow, colorRed},
+	}
+)
+
+func (p *palette) label(level Level) string {
+	if level < LevelDebug || level > LevelError {
+		return ""
+	}
+	return p.labels[level]
+}
+
+func (p *palette) message(level Level) string {
+	if level < LevelDebug || level > LevelError {
+		return ""
+	}
+	return p.messages[level]
+}
+
+func (p *palette) paint(b *strings.Builder, color string, s string) {
+	if color == "" {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(s)
+	b.WriteString(colorNone)
+}
+
+func levelLabel(level Level) string {
+	switch level {
+	case LevelDebug:
+		return "DBG"
+	case LevelInfo:
+		return "INF"
+	case LevelWarn:
+		return "WRN"
+	case LevelError:
+		return "ERR"
+	}
+	return "???"
+}
+
+// Returns a started logger that is independent from the default LoggerInstance
+func NewConsoleLogger(opts ConsoleLoggerOptions) *ConsoleLoggerImpl {
+	lgr := &ConsoleLoggerImpl{out: opts.Output}
+	lgr.sink = lgr
+	lgr.SetLayout(opts.Layout)
+	lgr.SetColorMode(opts.Color)
+	lgr.SetTimeFormat(opts.TimeFormat)
+	lgr.SetOverflowPolicy(opts.Overflow, opts.SampleRate)
+	lgr.initQueue(bufferSize(opts.BufferSize))
+	lgr.start(lgr.run)
+	return lgr
+}
+
 func (lgr *ConsoleLoggerImpl) init() {
-	lgr.messages = make(chan string, Logbuffersize)
+	lgr.sink = lgr
+	lgr.initQueue(Logbuffersize)
 }
 
-func (logger *ConsoleLoggerImpl) StartLogger() {
-	fmt.Println("Starting Logger")
-	loggerlogonce.Do(func() {
-		go func() {
-			for msg := range logger.messages {
-				fmt.Print(msg)
-			}
-		}()
-	})
+// Call it before the logger is started
+func (lgr *ConsoleLoggerImpl) SetLayout(layout ConsoleLayout) {
+	lgr.layout = layout
 }
 
-func (logger *ConsoleLoggerImpl) StopLogger() {
-	close(logger.messages)
+// Call it before the logger is started
+func (lgr *ConsoleLoggerImpl) SetColorMode(mode ColorMode) {
+	lgr.color = mode
 }
 
-func (logger *ConsoleLoggerImpl) Write(message string) {
-	logger.messages <- time.Now().Format(time.UnixDate) + " : " + message + "\n"
+func (logger *ConsoleLoggerImpl) StartLogger() {
+	fmt.Println("Starting Logger")
+	logger.start(logger.run)
 }
 
-func (logger *ConsoleLoggerImpl) WriteRequest(message string, uuid string) {
-	logger.Write(uuid + " : " + message)
+// Picks the output and the palette, run calls it so the setters work until the logger is started
+func (logger *ConsoleLoggerImpl) prepare() {
+	logger.ready.Do(logger.pick)
 }
 
-func (logger *ConsoleLoggerImpl) WriteErr(err error) (errnum int) {
-	if err != nil {
-		logger.Write("Error: " + err.Error())
-		errnum = 1
+func (logger *ConsoleLoggerImpl) pick() {
+	if logger.out == nil {
+		logger.out = os.Stdout
+	}
+	logger.palette = noColors
+	if logger.color == ColorAlways || (logger.color == ColorAuto && colorSupported(logger.out)) {
+		logger.palette = ansiColors
 	}
-	return errnum
 }
 
-func (logger *ConsoleLoggerImpl) WriteErrRequest(err error, uuid string) (errnum int) {
-	if err != nil {
-		logger.Write(uuid + " : Error: " + err.Error())
-		errnum = 1
+// Colors are used on terminals unless NO_COLOR is set to a non empty value (no-color.org) or TERM is dumb
+func colorSupported(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
 	}
-	return errnum
+	return isTerminal(out)
 }
 
-func (logger *ConsoleLoggerImpl) WriteErrMsgRequest(err error, message string, uuid string) (errnum int) {
-	if err != nil {
-		logger.Write(uuid + " " + message + ": Error: " + err.Error())
-		errnum = 1
+// Replaced by the tests
+var isTerminal = func(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
 	}
-	return errnum
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
-func (logger *ConsoleLoggerImpl) WriteDebug(message string) {
-	if DEBUG {
-		logger.Write(message)
+func (logger *ConsoleLoggerImpl) run() {
+	logger.prepare()
+	report := time.NewTicker(DropReportInterval)
+	defer report.Stop()
+	for {
+		select {
+		case msg, ok := <-logger.messages:
+			if !ok {
+				return
+			}
+			logger.write(msg)
+			logger.markProcessed(1)
+		case <-report.C:
+			if msg, ok := logger.droppedReport(logger.now()); ok {
+				logger.write(msg)
+			}
+		}
 	}
 }
 
-func (logger *ConsoleLoggerImpl) WriteRequestDebug(message string, uuid string) {
-	if DEBUG {
-		logger.WriteRequest(message, uuid)
-	}
+func (logger *ConsoleLoggerImpl) write(e *Entry) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	start := time.Now()
+	n, err := io.WriteString(logger.out, e.format(logger.layout, logger.timeFormat, logger.palette))
+	logger.metrics.observeWrite(n, time.Since(start), err)
 }
 
-func (logger *ConsoleLoggerImpl) WriteErrDebug(err error) (errnum int) {
-	if err != nil {
-		logger.WriteDebug("Error: " + err.Error())
-		errnum = 1
-	}
-	return errnum
+// Safe to call more than once
+func (logger *ConsoleLoggerImpl) StopLogger() {
+	logger.stop()
 }
 
-func (logger *ConsoleLoggerImpl) WriteErrRequestDebug(err error, uuid string) (errnum int) {
-	if err != nil {
-		logger.WriteDebug(uuid + " : Error: " + err.Error())
-		errnum = 1
-	}
-	return errnum
+// Waits until the messages written so far are printed
+func (logger *ConsoleLoggerImpl) Flush(ctx context.Context) error {
+	return logger.flush(ctx)
+}
+
+// Stops the logger and waits until the queued messages are printed
+func (logger *ConsoleLoggerImpl) Close(ctx context.Context) error {
+	return logger.close(ctx, logger.run, func() error { return nil })
+}
+
+// Prints e directly once the queued messages were printed (or ctx expired)
+func (logger *ConsoleLoggerImpl) writeCrash(ctx context.Context, e *Entry) {
+	logger.flush(ctx)
+	logger.prepare()
+	logger.write(e)
+}
+
+func (logger *ConsoleLoggerImpl) log(e *Entry) {
+	logger.push(e)
 }
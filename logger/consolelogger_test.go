@@ -0,0 +1,76 @@
+package Logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func consoleOutput(t *testing.T, opts ConsoleLoggerOptions, write func(lgr *ConsoleLoggerImpl)) string {
+	var out bytes.Buffer
+	opts.Output = &out
+	lgr := NewConsoleLogger(opts)
+	write(lgr)
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	return out.String()
+}
+
+func Test_consoleLayouts(t *testing.T) {
+	write := func(lgr *ConsoleLoggerImpl) {
+		lgr.WriteRequest("hello", "uuid-1")
+		lgr.WriteErr(errors.New("boom"))
+	}
+
+	full := strings.Split(consoleOutput(t, ConsoleLoggerOptions{}, write), "\n")
+	Testing.AssertTrue(t, strings.HasSuffix(full[0], " : uuid-1 : hello"))
+	Testing.AssertTrue(t, strings.HasSuffix(full[1], " : Error: boom"))
+
+	compact := strings.Split(consoleOutput(t, ConsoleLoggerOptions{Layout: LayoutCompact}, write), "\n")
+	Testing.AssertEqual(t, len("15:04:05.000 INF [uuid-1] hello"), len(compact[0]))
+	Testing.AssertTrue(t, strings.HasSuffix(compact[0], " INF [uuid-1] hello"))
+	Testing.AssertTrue(t, strings.HasSuffix(compact[1], " ERR Error: boom"))
+}
+
+func Test_consoleColors(t *testing.T) {
+	write := func(lgr *ConsoleLoggerImpl) {
+		lgr.WriteRequest("hello", "uuid-1")
+		lgr.WriteErr(errors.New("boom"))
+	}
+
+	// A buffer isn't a terminal
+	Testing.AssertFalse(t, strings.Contains(consoleOutput(t, ConsoleLoggerOptions{}, write), "\033["))
+	Testing.AssertFalse(t, strings.Contains(consoleOutput(t, ConsoleLoggerOptions{Color: ColorNever}, write), "\033["))
+
+	colored := consoleOutput(t, ConsoleLoggerOptions{Layout: LayoutCompact, Color: ColorAlways}, write)
+	Testing.AssertTrue(t, strings.Contains(colored, colorGreen+"INF"+colorNone))
+	Testing.AssertTrue(t, strings.Contains(colored, colorCyan+"[uuid-1]"+colorNone))
+	Testing.AssertTrue(t, strings.Contains(colored, colorRed+"Error: boom"+colorNone))
+}
+
+func Test_consoleColorDetection(t *testing.T) {
+	write := func(lgr *ConsoleLoggerImpl) {
+		lgr.WriteErr(errors.New("boom"))
+	}
+	terminal := isTerminal
+	defer func() { isTerminal = terminal }()
+	isTerminal = func(io.Writer) bool { return true }
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	Testing.AssertTrue(t, strings.Contains(consoleOutput(t, ConsoleLoggerOptions{}, write), "\033["))
+
+	t.Setenv("NO_COLOR", "1")
+	Testing.AssertFalse(t, colorSupported(&bytes.Buffer{}))
+	Testing.AssertFalse(t, strings.Contains(consoleOutput(t, ConsoleLoggerOptions{}, write), "\033["))
+	// ColorAlways ignores NO_COLOR
+	Testing.AssertTrue(t, strings.Contains(consoleOutput(t, ConsoleLoggerOptions{Color: ColorAlways}, write), "\033["))
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "dumb")
+	Testing.AssertFalse(t, colorSupported(&bytes.Buffer{}))
+}
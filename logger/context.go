@@ -0,0 +1,78 @@
+package Logger
+
+import "context"
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceKey
+	fieldsKey
+	loggerKey
+)
+
+type traceIDs struct {
+	traceID string
+	spanID  string
+}
+
+// Shorthand for Field{Key: key, Value: value}
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Returns a context carrying the request id picked up by the *Ctx methods
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// Returns the request id of the context, empty if there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// Returns a context carrying trace and span ids, the *Ctx methods log them as trace_id and span_id
+func WithTrace(ctx context.Context, traceID string, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey, traceIDs{traceID: traceID, spanID: spanID})
+}
+
+// Returns a context carrying fields in addition to the ones already attached to ctx
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	existing, _ := ctx.Value(fieldsKey).([]Field)
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsKey, merged)
+}
+
+// Returns a context carrying logger, get it back with FromContext
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// Returns the logger of the context, LoggerInstance() if there is none
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerKey).(Logger); ok {
+		return logger
+	}
+	return LoggerInstance()
+}
+
+// Copies the request id, trace ids and fields of ctx into the entry
+func (e *Entry) addContext(ctx context.Context) {
+	if id := RequestID(ctx); id != "" {
+		e.RequestID = id
+	}
+	if trace, ok := ctx.Value(traceKey).(traceIDs); ok {
+		if trace.traceID != "" {
+			e.Fields = append(e.Fields, Field{Key: "trace_id", Value: trace.traceID})
+		}
+		if trace.spanID != "" {
+			e.Fields = append(e.Fields, Field{Key: "span_id", Value: trace.spanID})
+		}
+	}
+	if fields, ok := ctx.Value(fieldsKey).([]Field); ok {
+		e.Fields = append(e.Fields, fields...)
+	}
+}
@@ -0,0 +1,37 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_contextLogging(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	ctx := NewContext(context.Background(), lgr)
+	ctx = WithRequestID(ctx, "uuid")
+	ctx = WithTrace(ctx, "trace", "span")
+	ctx = WithFields(ctx, F("user", 7))
+
+	FromContext(ctx).InfoCtx(ctx, "hello")
+	Testing.AssertEqual(t, 1, FromContext(ctx).ErrorMsgCtx(WithFields(ctx, F("table", "users")), errors.New("some error"), "query failed"))
+	Testing.AssertEqual(t, 0, lgr.ErrorCtx(ctx, nil))
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	content, _ := os.ReadFile(lgr.filepath)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	Testing.AssertEqual(t, 2, len(lines))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : uuid : hello trace_id=trace span_id=span user=7"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], " : uuid query failed: Error: some error trace_id=trace span_id=span user=7 table=users"))
+}
+
+func Test_fromContextDefault(t *testing.T) {
+	Testing.AssertEqual(t, "", RequestID(context.Background()))
+	Testing.AssertNotPanic(t, func() {
+		FromContext(context.Background()).InfoCtx(context.Background(), "dropped")
+	})
+}
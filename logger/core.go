@@ -0,0 +1,324 @@
+package Logger
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Receives finished entries, every Logger implementation provides one
+type entrySink interface {
+	log(e *Entry)
+}
+
+// Implements the Write* methods of Logger by building an Entry and handing it to the sink of the embedding logger
+type core struct {
+	sink     entrySink
+	redactor *Redactor
+	sampler  *Sampler
+	caller   bool
+	stack    bool
+	metrics  metrics
+	hooks    []hook
+
+	clock      Clock
+	location   *time.Location
+	timeFormat string
+}
+
+// Record the file, function and line the message was logged from. Call it before the logger is used.
+func (c *core) SetCallerCapture(enabled bool) {
+	c.caller = enabled
+}
+
+func (c *core) capturesCaller() bool {
+	return c.caller
+}
+
+// Record a stack trace for Error level messages. Call it before the logger is used.
+func (c *core) SetStackCapture(enabled bool) {
+	c.stack = enabled
+}
+
+// Messages are sampled by s before an entry is built for them, nil turns sampling off. Call it before the logger is used.
+func (c *core) SetSampler(s *Sampler) {
+	c.sampler = s
+}
+
+// Every entry is redacted by r before it reaches the sink, nil turns redaction off. Call it before the logger is used.
+func (c *core) SetRedactor(r *Redactor) {
+	c.redactor = r
+}
+
+// The entry point of every message, loggers that forward entries (e.g. MultiLogger) call it on their children
+func (c *core) logEntry(e *Entry) {
+	if c.sink == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = c.now()
+	}
+	if !c.accept(e) {
+		return
+	}
+	c.sink.log(e)
+}
+
+// Runs the hooks, counts and redacts the entry, false if a hook dropped it
+func (c *core) accept(e *Entry) bool {
+	for _, h := range c.hooks {
+		if h.runsFor(e.Level) && !h.fn(e) {
+			return false
+		}
+	}
+	c.metrics.countMessage(e.Level)
+	if c.redactor != nil {
+		c.redactor.redactEntry(e)
+	}
+	return true
+}
+
+func (c *core) emit(level Level, message string, err error, uuid string, fields ...Field) {
+	if c.sink == nil {
+		return
+	}
+	e := c.newEntry(level, message, err)
+	c.reportSuppressed()
+	if e == nil {
+		return
+	}
+	e.RequestID = uuid
+	e.Fields = append(e.Fields, fields...)
+	c.logEntry(e)
+}
+
+func (c *core) emitCtx(ctx context.Context, level Level, message string, err error) {
+	if c.sink == nil {
+		return
+	}
+	e := c.newEntry(level, message, err)
+	c.reportSuppressed()
+	if e == nil {
+		return
+	}
+	e.addContext(ctx)
+	c.logEntry(e)
+}
+
+// Logs the summaries of the messages the sampler forgot about since the last call
+func (c *core) reportSuppressed() {
+	if c.sampler == nil {
+		return
+	}
+	for _, e := range c.sampler.takeSummaries(c.now()) {
+		c.logEntry(e)
+	}
+}
+
+// Returns nil if the sampler suppressed the message.
+// It has to be called directly by emit / emitCtx which are called directly by the exported methods (see callerSkip).
+func (c *core) newEntry(level Level, message string, err error) *Entry {
+	now := c.now()
+	var suppressed uint64
+	if c.sampler != nil {
+		var ok bool
+		if ok, suppressed = c.sampler.allow(level, message, err, now); !ok {
+			return nil
+		}
+	}
+	e := &Entry{Time: now, Level: level, Message: message, Err: err}
+	if c.caller {
+		e.Caller = callerAt(callerSkip)
+	}
+	if c.stack && level >= LevelError {
+		e.Stack = stackAt(callerSkip)
+	}
+	if suppressed > 0 {
+		e.Fields = append(e.Fields, Field{Key: "suppressed", Value: suppressed})
+	}
+	return e
+}
+
+func (c *core) Write(message string) {
+	c.emit(LevelInfo, message, nil, "")
+}
+
+func (c *core) WriteRequest(message string, uuid string) {
+	c.emit(LevelInfo, message, nil, uuid)
+}
+
+func (c *core) WriteErr(err error) (errnum int) {
+	if err != nil {
+		c.emit(LevelError, "", err, "")
+		errnum = 1
+	}
+	return errnum
+}
+
+func (c *core) WriteErrRequest(err error, uuid string) (errnum int) {
+	if err != nil {
+		c.emit(LevelError, "", err, uuid)
+		errnum = 1
+	}
+	return errnum
+}
+
+func (c *core) WriteErrMsgRequest(err error, message string, uuid string) (errnum int) {
+	if err != nil {
+		c.emit(LevelError, message, err, uuid)
+		errnum = 1
+	}
+	return errnum
+}
+
+func (c *core) WriteDebug(message string) {
+	if DEBUG {
+		c.emit(LevelDebug, message, nil, "")
+	}
+}
+
+func (c *core) WriteRequestDebug(message string, uuid string) {
+	if DEBUG {
+		c.emit(LevelDebug, message, nil, uuid)
+	}
+}
+
+func (c *core) WriteErrDebug(err error) (errnum int) {
+	if err != nil {
+		if DEBUG {
+			c.emit(LevelDebug, "", err, "")
+		}
+		errnum = 1
+	}
+	return errnum
+}
+
+func (c *core) WriteErrRequestDebug(err error, uuid string) (errnum int) {
+	if err != nil {
+		if DEBUG {
+			c.emit(LevelDebug, "", err, uuid)
+		}
+		errnum = 1
+	}
+	return errnum
+}
+
+func (c *core) WriteErrMsgRequestDebug(err error, message string, uuid string) (errnum int) {
+	if err != nil {
+		if DEBUG {
+			c.emit(LevelDebug, message, err, uuid)
+		}
+		errnum = 1
+	}
+	return errnum
+}
+
+func (c *core) DebugCtx(ctx context.Context, message string) {
+	if DEBUG {
+		c.emitCtx(ctx, LevelDebug, message, nil)
+	}
+}
+
+func (c *core) InfoCtx(ctx context.Context, message string) {
+	c.emitCtx(ctx, LevelInfo, message, nil)
+}
+
+func (c *core) WarnCtx(ctx context.Context, message string) {
+	c.emitCtx(ctx, LevelWarn, message, nil)
+}
+
+func (c *core) ErrorCtx(ctx context.Context, err error) (errnum int) {
+	if err != nil {
+		c.emitCtx(ctx, LevelError, "", err)
+		errnum = 1
+	}
+	return errnum
+}
+
+func (c *core) ErrorMsgCtx(ctx context.Context, err error, message string) (errnum int) {
+	if err != nil {
+		c.emitCtx(ctx, LevelError, message, err)
+		errnum = 1
+	}
+	return errnum
+}
+
+// The message part of the entry: message, Error: err or message: Error: err
+func (e *Entry) text() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return "Error: " + e.Err.Error()
+	}
+	return e.Message + ": Error: " + e.Err.Error()
+}
+
+// The entry in the given layout with timeFormat (the default of the layout if empty), colored by p
+func (e *Entry) format(layout ConsoleLayout, timeFormat string, p *palette) string {
+	if layout == LayoutJSON {
+		return string(append(e.json(), '\n'))
+	}
+	var b strings.Builder
+	if layout == LayoutCompact {
+		if timeFormat == "" {
+			timeFormat = compactTimeFormat
+		}
+		p.paint(&b, p.dim, e.Time.Format(timeFormat))
+		b.WriteByte(' ')
+		p.paint(&b, p.label(e.Level), levelLabel(e.Level))
+		b.WriteByte(' ')
+		if e.RequestID != "" {
+			p.paint(&b, p.requestID, "["+e.RequestID+"]")
+			b.WriteByte(' ')
+		}
+	} else {
+		if timeFormat == "" {
+			timeFormat = time.UnixDate
+		}
+		p.paint(&b, p.dim, e.Time.Format(timeFormat))
+		b.WriteString(" : ")
+		if e.RequestID != "" {
+			p.paint(&b, p.requestID, e.RequestID)
+			// Kept from the original WriteErrMsgRequest output
+			if e.Err != nil && e.Message != "" {
+				b.WriteByte(' ')
+			} else {
+				b.WriteString(" : ")
+			}
+		}
+	}
+	p.paint(&b, p.message(e.Level), e.text())
+	for _, f := range e.Fields {
+		b.WriteByte(' ')
+		p.paint(&b, p.dim, f.Key)
+		b.WriteByte('=')
+		b.WriteString(fieldValue(f.Value))
+	}
+	if e.Caller != nil {
+		b.WriteByte(' ')
+		p.paint(&b, p.dim, "caller")
+		b.WriteByte('=')
+		b.WriteString(e.Caller.String())
+	}
+	b.WriteByte('\n')
+	if e.Stack != "" {
+		for _, frame := range strings.SplitAfter(strings.TrimSuffix(e.Stack, "\n"), "\n") {
+			b.WriteByte('\t')
+			p.paint(&b, p.dim, frame)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Quotes the value if it would be ambiguous in a key=value list
+func fieldValue(v any) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " =\"\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// How long LogPanic and Exit wait for the queued messages to be written
+var CrashFlushTimeout = 5 * time.Second
+
+// Replaced in tests
+var exit = os.Exit
+
+// Implemented by the sinks that can write an entry without their writer goroutine
+type crashWriter interface {
+	writeCrash(ctx context.Context, e *Entry)
+}
+
+func (c *core) crash(e *Entry) {
+	if c.sink == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = c.now()
+	}
+	if !c.accept(e) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), CrashFlushTimeout)
+	defer cancel()
+	if w, ok := c.sink.(crashWriter); ok {
+		w.writeCrash(ctx, e)
+		return
+	}
+	// Synchronous sinks (Null, Slog, Memory) are done after log, the rest at least get the entry queued and flushed
+	c.sink.log(e)
+	if f, ok := c.sink.(interface{ Flush(context.Context) error }); ok {
+		f.Flush(ctx)
+	}
+}
+
+// Logs a recovered panic value with the stack of the current goroutine on every sink of logger (LoggerInstance if nil).
+// The messages queued before it are flushed first, then the panic is written synchronously, so it is on disk even if
+// the process dies right after. uuid may be empty.
+func LogPanic(logger Logger, value any, uuid string) {
+	if logger == nil {
+		logger = LoggerInstance()
+	}
+	err, ok := value.(error)
+	if !ok {
+		err = fmt.Errorf("%v", value)
+	}
+	logger.crash(&Entry{Level: LevelError, RequestID: uuid, Message: "panic", Err: err, Stack: string(debug.Stack())})
+}
+
+// Defer it at the top of a goroutine: a panic is recovered and logged with LogPanic instead of crashing the process
+//
+//	defer Logger.RecoverAndLog(lgr)
+func RecoverAndLog(logger Logger) {
+	if r := recover(); r != nil {
+		LogPanic(logger, r, "")
+	}
+}
+
+// Recovers panics of the handlers, logs them with LogPanic and the request id of the context, and responds with 500.
+// http.ErrAbortHandler is passed on, net/http uses it to abort a response on purpose.
+func RecoverMiddleware(logger Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+				lgr := logger
+				if lgr == nil {
+					lgr = FromContext(r.Context())
+				}
+				LogPanic(lgr, rec, RequestID(r.Context()))
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Closes logger (LoggerInstance if nil) so the queued messages are written, waiting at most CrashFlushTimeout, then
+// calls os.Exit(code). Use it instead of os.Exit, which doesn't run deferred calls.
+func Exit(logger Logger, code int) {
+	if logger == nil {
+		logger = LoggerInstance()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), CrashFlushTimeout)
+	logger.Close(ctx)
+	cancel()
+	exit(code)
+}
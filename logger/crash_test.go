@@ -0,0 +1,94 @@
+package Logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_logPanic(t *testing.T) {
+	file := newTestFileLogger(t, FsyncNever)
+	memory := NewMemoryLogger(10)
+	lgr := NewMultiLogger(MultiSink{Logger: file}, MultiSink{Logger: memory})
+	lgr.Write("before")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer RecoverAndLog(lgr)
+		panic("something broke")
+	}()
+	<-done
+
+	// Written without Flush or Close
+	content, _ := os.ReadFile(file.filepath)
+	lines := strings.Split(string(content), "\n")
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : before"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], " : panic: Error: something broke"))
+	Testing.AssertTrue(t, strings.Contains(string(content), "\tgoroutine "))
+	Testing.AssertTrue(t, strings.Contains(string(content), "Test_logPanic"))
+	Testing.AssertEqual(t, 1, len(memory.Query(MemoryQuery{MinLevel: LevelError, Contains: "something broke"})))
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+}
+
+func Test_recoverMiddleware(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	handler := AccessLog(lgr, AccessLogOptions{})(RecoverMiddleware(lgr)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(errors.New("handler failed"))
+	})))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	Testing.AssertEqual(t, http.StatusInternalServerError, rec.Code)
+
+	panics := lgr.Query(MemoryQuery{Contains: "handler failed"})
+	Testing.AssertEqual(t, 1, len(panics))
+	Testing.AssertEqual(t, rec.Header().Get("X-Request-ID"), panics[0].RequestID)
+	Testing.AssertTrue(t, panics[0].Stack != "")
+
+	aborting := RecoverMiddleware(lgr)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	Testing.AssertPanic(t, func() {
+		aborting.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func Test_exitFlushes(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	lgr := newTestFileLogger(t, FsyncNever)
+	lgr.Write("last words")
+	Exit(lgr, 3)
+	Testing.AssertEqual(t, 3, code)
+	content, _ := os.ReadFile(lgr.filepath)
+	Testing.AssertTrue(t, strings.HasSuffix(string(content), " : last words\n"))
+}
+
+func Test_logPanicConsoleRace(t *testing.T) {
+	var out bytes.Buffer
+	lgr := NewConsoleLogger(ConsoleLoggerOptions{Output: &out, Overflow: OverflowDropNewest})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				lgr.Write("busy")
+			}
+		}()
+	}
+	LogPanic(lgr, "crashed", "")
+	wg.Wait()
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertTrue(t, strings.Contains(out.String(), " : panic: Error: crashed\n"))
+}
@@ -0,0 +1,244 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Errors and drops older than this don't affect the health of a sink
+var HealthWindow = time.Minute
+
+// Health of a Dispatcher sink
+type SinkStatus int8
+
+const (
+	SinkHealthy SinkStatus = iota
+	// The channel is at least 3/4 full or messages were dropped within the HealthWindow
+	SinkDegraded
+	// The sink panicked or failed to write within the HealthWindow
+	SinkFailed
+)
+
+func (status SinkStatus) String() string {
+	switch status {
+	case SinkHealthy:
+		return "healthy"
+	case SinkDegraded:
+		return "degraded"
+	case SinkFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+func (status SinkStatus) MarshalText() ([]byte, error) {
+	return []byte(status.String()), nil
+}
+
+// A snapshot of the state of a Dispatcher sink
+type SinkHealth struct {
+	Name          string     `json:"name"`
+	Status        SinkStatus `json:"status"`
+	QueueDepth    int        `json:"queue_depth"`
+	QueueCapacity int        `json:"queue_capacity"`
+	Dropped       uint64     `json:"dropped"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+type dispatchWorker struct {
+	multiSink
+	logQueue
+	name        string
+	lastDropped uint64 // guarded by multiSink.mutex, like droppedAt and failedAt
+	droppedAt   time.Time
+	failedAt    time.Time
+}
+
+// multiSink.call that also remembers when the sink failed, for Health
+func (w *dispatchWorker) call(f func(Logger) error) error {
+	err := w.multiSink.call(f)
+	if err != nil {
+		w.mutex.Lock()
+		w.failedAt = time.Now()
+		w.mutex.Unlock()
+	}
+	return err
+}
+
+// Returns a started dispatcher. Every sink gets its own channel and goroutine, so a slow sink only holds up the
+// others once its channel is full (and not even then with a dropping overflow policy). Entries reach each sink in
+// the order they were logged. The sinks are expected to be initialized already, like the ones of NewMultiLogger.
+// It pays off for synchronous or slow sinks, buffered loggers (File, Console) already have a channel of their own
+// and only gain isolation from each other, for the price of an extra hop (see the Fanout benchmarks).
+func NewDispatcher(sinks ...DispatcherSink) *Dispatcher {
+	lgr := &Dispatcher{}
+	lgr.sink = lgr
+	for _, s := range sinks {
+		w := &dispatchWorker{multiSink: multiSink{MultiSink: MultiSink{Logger: s.Logger, Level: s.Level}}, name: s.Name}
+		if w.name == "" {
+			w.name = TypeName(s.Logger)
+		}
+		w.SetOverflowPolicy(s.Overflow, s.SampleRate)
+		w.initQueue(bufferSize(s.BufferSize))
+		lgr.workers = append(lgr.workers, w)
+		w.start(w.run)
+	}
+	return lgr
+}
+
+func (lgr *Dispatcher) init() {
+	lgr.sink = lgr
+}
+
+// Starts the goroutines of the sinks and the sinks themselves
+func (logger *Dispatcher) StartLogger() {
+	for _, w := range logger.workers {
+		w.start(w.run)
+		w.call(func(l Logger) error {
+			l.StartLogger()
+			return nil
+		})
+	}
+}
+
+// Safe to call more than once, the queued entries are delivered by Close
+func (logger *Dispatcher) StopLogger() {
+	for _, w := range logger.workers {
+		w.stop()
+	}
+}
+
+// Waits until every sink received the entries written so far and flushed them
+func (logger *Dispatcher) Flush(ctx context.Context) error {
+	var errs []error
+	for _, w := range logger.workers {
+		if err := w.flush(ctx); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := w.call(func(l Logger) error { return l.Flush(ctx) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stops accepting entries, delivers the queued ones to every sink (in parallel) and closes the sinks
+func (logger *Dispatcher) Close(ctx context.Context) error {
+	errs := make([]error, len(logger.workers))
+	done := make(chan struct{})
+	for i, w := range logger.workers {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			errs[i] = w.close(ctx, w.run, func() error {
+				return w.call(func(l Logger) error { return l.Close(ctx) })
+			})
+		}()
+	}
+	for range logger.workers {
+		<-done
+	}
+	return errors.Join(errs...)
+}
+
+// The state of every sink in the order they were passed to NewDispatcher
+func (logger *Dispatcher) Health() []SinkHealth {
+	health := make([]SinkHealth, len(logger.workers))
+	now := time.Now()
+	for i, w := range logger.workers {
+		depth, capacity, dropped := w.queueStats()
+		stats := w.Logger.Stats()
+		// Drops of the channel of the sink itself count as well
+		dropped += stats.Dropped
+		h := SinkHealth{Name: w.name, QueueDepth: depth, QueueCapacity: capacity, Dropped: dropped}
+
+		w.mutex.Lock()
+		if dropped != w.lastDropped {
+			w.lastDropped, w.droppedAt = dropped, now
+		}
+		droppedAt, failedAt, lastErr := w.droppedAt, w.failedAt, w.lastErr
+		w.mutex.Unlock()
+		if stats.LastErrorTime.After(failedAt) {
+			failedAt, lastErr = stats.LastErrorTime, errors.New(stats.LastError)
+		}
+		if lastErr != nil {
+			h.LastError = lastErr.Error()
+		}
+
+		switch {
+		case !failedAt.IsZero() && now.Sub(failedAt) < HealthWindow:
+			h.Status = SinkFailed
+		case depth*4 >= capacity*3 || (!droppedAt.IsZero() && now.Sub(droppedAt) < HealthWindow):
+			h.Status = SinkDegraded
+		}
+		health[i] = h
+	}
+	return health
+}
+
+// Messages are counted as the Dispatcher received them, the rest is summed up from the sinks and their channels
+func (logger *Dispatcher) Stats() Stats {
+	stats := logger.core.Stats()
+	for _, w := range logger.workers {
+		sink := w.Logger.Stats()
+		sink.QueueDepth, sink.QueueCapacity, sink.Dropped = sink.QueueDepth+len(w.messages), sink.QueueCapacity+cap(w.messages), sink.Dropped+w.dropped.Load()
+		stats.merge(sink)
+	}
+	return stats
+}
+
+func (w *dispatchWorker) run() {
+	report := time.NewTicker(DropReportInterval)
+	defer report.Stop()
+	for {
+		select {
+		case msg, ok := <-w.messages:
+			if !ok {
+				return
+			}
+			w.deliver(msg)
+			w.markProcessed(1)
+		case <-report.C:
+			if msg, ok := w.droppedReport(w.Logger.now()); ok {
+				w.deliver(msg)
+			}
+		}
+	}
+}
+
+func (w *dispatchWorker) deliver(e *Entry) {
+	w.call(func(l Logger) error {
+		l.logEntry(e)
+		return nil
+	})
+}
+
+// Waits for the queued entries of every sink, then hands the crash entry to each sink synchronously
+func (logger *Dispatcher) writeCrash(ctx context.Context, e *Entry) {
+	for _, w := range logger.workers {
+		w.flush(ctx)
+		if e.Level < w.Level {
+			continue
+		}
+		entry := *e
+		entry.Fields = slices.Clip(entry.Fields)
+		w.call(func(l Logger) error {
+			l.crash(&entry)
+			return nil
+		})
+	}
+}
+
+func (logger *Dispatcher) log(e *Entry) {
+	for _, w := range logger.workers {
+		if e.Level < w.Level {
+			continue
+		}
+		// Every sink gets its own copy, they may redact it or extend its fields on their own goroutine
+		entry := *e
+		entry.Fields = slices.Clip(entry.Fields)
+		w.push(&entry)
+	}
+}
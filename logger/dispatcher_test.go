@@ -0,0 +1,132 @@
+package Logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+// A sink that holds every entry until gate is closed
+func gatedSink(gate chan struct{}) *MemoryLoggerImpl {
+	lgr := NewMemoryLogger(100)
+	lgr.AddHook(func(e *Entry) bool {
+		<-gate
+		return true
+	})
+	return lgr
+}
+
+func Test_dispatcherOrder(t *testing.T) {
+	first, second := NewMemoryLogger(100), NewMemoryLogger(100)
+	lgr := NewDispatcher(DispatcherSink{Logger: first}, DispatcherSink{Logger: second, Level: LevelWarn, BufferSize: 4})
+	var want []string
+	for i := 0; i < 50; i++ {
+		want = append(want, fmt.Sprint(i))
+		lgr.Write(fmt.Sprint(i))
+	}
+	lgr.WarnCtx(context.Background(), "warning")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	Testing.AssertEqual(t, strings.Join(want, ",")+",warning", memoryMessages(first.Entries()))
+	Testing.AssertEqual(t, "warning", memoryMessages(second.Entries()))
+	Testing.AssertEqual(t, uint64(50), lgr.Stats().Messages["info"])
+}
+
+func Test_dispatcherSlowSink(t *testing.T) {
+	gate := make(chan struct{})
+	slow, fast := gatedSink(gate), NewMemoryLogger(100)
+	lgr := NewDispatcher(
+		DispatcherSink{Name: "slow", Logger: slow, BufferSize: 2, Overflow: OverflowDropNewest},
+		DispatcherSink{Name: "fast", Logger: fast},
+	)
+	lgr.Write("0")
+	// Wait until the slow sink is stuck on the first entry
+	deadline := time.Now().Add(5 * time.Second)
+	for lgr.Health()[0].QueueDepth > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 1; i < 10; i++ {
+		lgr.Write(fmt.Sprint(i))
+	}
+	// The fast sink gets everything while the slow one is stuck on its first entry
+	for len(fast.Entries()) < 10 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	Testing.AssertEqual(t, 10, len(fast.Entries()))
+
+	health := lgr.Health()
+	Testing.AssertEqual(t, "slow", health[0].Name)
+	Testing.AssertEqual(t, SinkDegraded, health[0].Status)
+	Testing.AssertEqual(t, uint64(7), health[0].Dropped)
+	Testing.AssertEqual(t, SinkHealthy, health[1].Status)
+
+	close(gate)
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertEqual(t, "0,1,2", memoryMessages(slow.Entries()))
+}
+
+func Test_dispatcherFailedSink(t *testing.T) {
+	broken := NewMemoryLogger(10)
+	broken.AddHook(func(e *Entry) bool {
+		panic("broken sink")
+	})
+	healthy := NewMemoryLogger(10)
+	lgr := NewDispatcher(DispatcherSink{Logger: broken}, DispatcherSink{Logger: healthy})
+	lgr.Write("hello")
+	Testing.AssertNotError(t, lgr.Flush(context.Background()))
+
+	health := lgr.Health()
+	Testing.AssertEqual(t, "*MemoryLoggerImpl", health[0].Name)
+	Testing.AssertEqual(t, SinkFailed, health[0].Status)
+	Testing.AssertEqual(t, "sink panicked: broken sink", health[0].LastError)
+	Testing.AssertEqual(t, SinkHealthy, health[1].Status)
+	Testing.AssertEqual(t, "hello", memoryMessages(healthy.Entries()))
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+}
+
+// A sink that takes a while for every entry, like a file synced after every write
+func slowSink() *MemoryLoggerImpl {
+	lgr := NewMemoryLogger(100)
+	lgr.AddHook(func(e *Entry) bool {
+		time.Sleep(20 * time.Microsecond)
+		return true
+	})
+	return lgr
+}
+
+func benchmarkFanout(b *testing.B, lgr Logger) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lgr.Write("benchmark message with a bit of payload to make it realistic")
+	}
+	// Draining the slow sink isn't part of the throughput
+	b.StopTimer()
+	lgr.Close(context.Background())
+}
+
+// The current design: the caller waits for every sink in turn
+func BenchmarkFanoutMultiLoggerSlowSink(b *testing.B) {
+	benchmarkFanout(b, NewMultiLogger(MultiSink{Logger: slowSink()}, MultiSink{Logger: NewMemoryLogger(100)}))
+}
+
+// Bounded by the slow sink once its channel is full, but the caller doesn't wait for each entry
+func BenchmarkFanoutDispatcherSlowSink(b *testing.B) {
+	benchmarkFanout(b, NewDispatcher(DispatcherSink{Logger: slowSink()}, DispatcherSink{Logger: NewMemoryLogger(100)}))
+}
+
+// The slow sink sheds load, the rest run at full speed
+func BenchmarkFanoutDispatcherSlowSinkDropping(b *testing.B) {
+	benchmarkFanout(b, NewDispatcher(DispatcherSink{Logger: slowSink(), Overflow: OverflowDropNewest}, DispatcherSink{Logger: NewMemoryLogger(100)}))
+}
+
+func BenchmarkFanoutMultiLoggerFiles(b *testing.B) {
+	benchmarkFanout(b, NewMultiLogger(MultiSink{Logger: newTestFileLogger(b, FsyncAlways)}, MultiSink{Logger: newTestFileLogger(b, FsyncNever)}))
+}
+
+func BenchmarkFanoutDispatcherFiles(b *testing.B) {
+	benchmarkFanout(b, NewDispatcher(DispatcherSink{Logger: newTestFileLogger(b, FsyncAlways)}, DispatcherSink{Logger: newTestFileLogger(b, FsyncNever)}))
+}
@@ -1,23 +1,56 @@
 package Logger
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"sync"
 	"time"
 )
 
+// Upper bound of bytes collected from the channel before they are written out in one go
+const maxBatchBytes = 64 * 1024
+
+const defaultFsyncInterval = time.Second
+
 func (lgr *FileLoggerImpl) SetLogFilePath(path string) {
 	lgr.initfilepath = path
 }
 
+// Call it before the logger is started, interval is only used by FsyncInterval (defaults to 1s)
+func (lgr *FileLoggerImpl) SetFsyncPolicy(policy FsyncPolicy, interval time.Duration) {
+	lgr.fsyncPolicy = policy
+	lgr.fsyncInterval = interval
+}
+
+// Returns a started logger that is independent from the default LoggerInstance, LOGFILE_GO_LOGGER is not consulted
+func NewFileLogger(opts FileLoggerOptions) (*FileLoggerImpl, error) {
+	lgr := &FileLoggerImpl{filepath: opts.Path}
+	lgr.sink = lgr
+	if lgr.filepath == "" {
+		lgr.filepath = "./log"
+	}
+	lgr.SetFsyncPolicy(opts.FsyncPolicy, opts.FsyncInterval)
+	lgr.layout = opts.Layout
+	lgr.SetTimeFormat(opts.TimeFormat)
+	lgr.SetOverflowPolicy(opts.Overflow, opts.SampleRate)
+	lgr.initQueue(bufferSize(opts.BufferSize))
+	if err := lgr.open(); err != nil {
+		return nil, err
+	}
+	lgr.start(lgr.run)
+	return lgr, nil
+}
+
 func (lgr *FileLoggerImpl) init() {
+	lgr.sink = lgr
 	if lgr.initfilepath == "" {
 		lgr.filepath = "./log"
 	} else {
 		lgr.filepath = lgr.initfilepath
 	}
-	lgr.messages = make(chan string, Logbuffersize)
+	lgr.initQueue(Logbuffersize)
 	envfp, envexist := os.LookupEnv("LOGFILE_GO_LOGGER")
 	if envexist {
 		if len(envfp) > 0 {
@@ -28,126 +61,170 @@ func (lgr *FileLoggerImpl) init() {
 	} else {
 		LoggerInstance().WriteDebug(fmt.Sprintf("LOGFILE_GO_LOGGER env doesn't exist using default value: %s !\n", lgr.filepath))
 	}
+	if err := lgr.open(); err != nil {
+		// We probably really don't want to continue execution without file backed logging
+		panic(err.Error())
+	}
+}
+
+func (lgr *FileLoggerImpl) open() error {
+	if lgr.fsyncInterval <= 0 {
+		lgr.fsyncInterval = defaultFsyncInterval
+	}
 	f, err := os.OpenFile(lgr.filepath, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0660)
 	if err != nil {
-		// We probably really don't want to continue execution without file backed logging
-		panic(fmt.Sprintf("Error opening or creating file: %s", err.Error()))
+		return fmt.Errorf("Error opening or creating file: %w", err)
 	}
 	lgr.logFile = f
 	lgr.mutex = &sync.Mutex{}
+	return nil
 }
 
 func (logger *FileLoggerImpl) StartLogger() {
 	fmt.Println("Starting FileLogger")
-	loggerlogonce.Do(func() {
-		go func() {
-			for msg := range logger.messages {
-
-				logger.mutex.Lock()
-				_, err := logger.logFile.WriteString(msg)
-				if err != nil {
-					fmt.Println(err.Error())
-					logger.logFile.Close()
-					logger.mutex.Unlock()
-					panic("Failed to write to file")
-				}
-				err = logger.logFile.Sync()
-				if err != nil {
-					logger.logFile.Close()
-					logger.mutex.Unlock()
+	logger.start(logger.run)
+}
 
-					panic("Failed to write to file")
+// Writes out everything that is waiting in the channel as one batch, then syncs according to the fsync policy
+func (logger *FileLoggerImpl) run() {
+	var tick <-chan time.Time
+	if logger.fsyncPolicy == FsyncInterval {
+		ticker := time.NewTicker(logger.fsyncInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	report := time.NewTicker(DropReportInterval)
+	defer report.Stop()
+
+	var batch bytes.Buffer
+	dirty := false
+	for {
+		select {
+		case msg, ok := <-logger.messages:
+			if !ok {
+				if dirty {
+					logger.sync()
+				}
+				return
+			}
+			batch.Reset()
+			batch.WriteString(msg.format(logger.layout, logger.timeFormat, noColors))
+			hasError := msg.Level == LevelError
+			count := 1
+			open := true
+		drain:
+			for batch.Len() < maxBatchBytes {
+				select {
+				case msg, ok = <-logger.messages:
+					if !ok {
+						open = false
+						break drain
+					}
+					batch.WriteString(msg.format(logger.layout, logger.timeFormat, noColors))
+					hasError = hasError || msg.Level == LevelError
+					count++
+				default:
+					break drain
 				}
-				logger.mutex.Unlock()
 			}
-		}()
-	})
-	// Technically we should do this but this will never run
-	// logger.mutex.Lock()
-	// logger.logFile.Close()
-	// logger.mutex.Unlock()
-}
-
-func (logger *FileLoggerImpl) StopLogger() {
-	close(logger.messages)
-}
-
-func (logger *FileLoggerImpl) Write(message string) {
-	logger.messages <- time.Now().Format(time.UnixDate) + " : " + message + "\n"
-}
-
-func (logger *FileLoggerImpl) WriteRequest(message string, uuid string) {
-	logger.Write(uuid + " : " + message)
-}
 
-func (logger *FileLoggerImpl) WriteErr(err error) (errnum int) {
-	if err != nil {
-		logger.Write("Error: " + err.Error())
-		errnum = 1
+			dirty = logger.writeBatch(batch.Bytes(), hasError) || dirty
+			logger.markProcessed(count)
+			if !open {
+				if dirty {
+					logger.sync()
+				}
+				return
+			}
+		case <-tick:
+			if dirty {
+				logger.sync()
+				dirty = false
+			}
+		case <-report.C:
+			if msg, ok := logger.droppedReport(logger.now()); ok {
+				dirty = logger.writeBatch([]byte(msg.format(logger.layout, logger.timeFormat, noColors)), false) || dirty
+			}
+		}
 	}
-	return errnum
 }
 
-func (logger *FileLoggerImpl) WriteErrRequest(err error, uuid string) (errnum int) {
-	if err != nil {
-		logger.Write(uuid + " : Error: " + err.Error())
-		errnum = 1
+// Writes b then syncs according to the fsync policy, returns true if the sync is left to the interval ticker
+func (logger *FileLoggerImpl) writeBatch(b []byte, hasError bool) bool {
+	logger.write(b)
+	switch logger.fsyncPolicy {
+	case FsyncAlways:
+		logger.sync()
+	case FsyncOnError:
+		if hasError {
+			logger.sync()
+		}
+	case FsyncInterval:
+		return true
 	}
-	return errnum
+	return false
 }
 
-func (logger *FileLoggerImpl) WriteErrMsgRequest(err error, message string, uuid string) (errnum int) {
+func (logger *FileLoggerImpl) write(b []byte) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	start := time.Now()
+	n, err := logger.logFile.Write(b)
+	logger.metrics.observeWrite(n, time.Since(start), err)
 	if err != nil {
-		logger.Write(uuid + " " + message + ": Error: " + err.Error())
-		errnum = 1
+		fmt.Println(err.Error())
+		logger.logFile.Close()
+		panic("Failed to write to file")
 	}
-	return errnum
 }
 
-func (logger *FileLoggerImpl) WriteDebug(message string) {
-	if DEBUG {
-		logger.Write(message)
+func (logger *FileLoggerImpl) sync() {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	err := logger.logFile.Sync()
+	if err != nil {
+		logger.metrics.writeFailed(err)
+		logger.logFile.Close()
+		panic("Failed to write to file")
 	}
 }
 
-func (logger *FileLoggerImpl) WriteRequestDebug(message string, uuid string) {
-	if DEBUG {
-		logger.WriteRequest(message, uuid)
-	}
+// Safe to call more than once, the file stays open until Close
+func (logger *FileLoggerImpl) StopLogger() {
+	logger.stop()
 }
 
-func (logger *FileLoggerImpl) WriteErrDebug(err error) (errnum int) {
-	if err != nil {
-		logger.WriteDebug("Error: " + err.Error())
-		errnum = 1
+// Waits until the messages written so far are in the file and syncs it
+func (logger *FileLoggerImpl) Flush(ctx context.Context) error {
+	if err := logger.flush(ctx); err != nil {
+		return err
 	}
-	return errnum
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	return logger.logFile.Sync()
 }
 
-func (logger *FileLoggerImpl) WriteErrRequestDebug(err error, uuid string) (errnum int) {
-	if err != nil {
-		logger.WriteDebug(uuid + " : Error: " + err.Error())
-		errnum = 1
-	}
-	return errnum
+// Stops the logger, waits until the queued messages are written and closes the file
+func (logger *FileLoggerImpl) Close(ctx context.Context) error {
+	return logger.close(ctx, logger.run, func() error {
+		logger.mutex.Lock()
+		defer logger.mutex.Unlock()
+		return logger.logFile.Close()
+	})
 }
 
-func (logger *FileLoggerImpl) WriteErrMsgRequestDebug(err error, message string, uuid string) (errnum int) {
-	if err != nil {
-		if DEBUG {
-			logger.WriteErrMsgRequest(err, message, uuid)
-		}
-		errnum = 1
-	}
-	return errnum
+// Writes e directly and syncs once the queued messages were written (or ctx expired), errors are ignored
+func (logger *FileLoggerImpl) writeCrash(ctx context.Context, e *Entry) {
+	logger.flush(ctx)
+	b := []byte(e.format(logger.layout, logger.timeFormat, noColors))
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	start := time.Now()
+	n, err := logger.logFile.Write(b)
+	logger.metrics.observeWrite(n, time.Since(start), err)
+	logger.logFile.Sync()
 }
 
-func (logger *ConsoleLoggerImpl) WriteErrMsgRequestDebug(err error, message string, uuid string) (errnum int) {
-	if err != nil {
-		if DEBUG {
-			logger.WriteErrMsgRequest(err, message, uuid)
-		}
-		errnum = 1
-	}
-	return errnum
+func (logger *FileLoggerImpl) log(e *Entry) {
+	logger.push(e)
 }
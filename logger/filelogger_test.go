@@ -0,0 +1,117 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func newTestFileLogger(tb testing.TB, policy FsyncPolicy) *FileLoggerImpl {
+	tb.Helper()
+	lgr, err := NewFileLogger(FileLoggerOptions{
+		Path:          filepath.Join(tb.TempDir(), "log"),
+		FsyncPolicy:   policy,
+		FsyncInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return lgr
+}
+
+func Test_fileLoggerBatching(t *testing.T) {
+	for _, policy := range []FsyncPolicy{FsyncAlways, FsyncInterval, FsyncOnError, FsyncNever} {
+		lgr := newTestFileLogger(t, policy)
+		for i := 0; i < 500; i++ {
+			lgr.Write("message")
+		}
+		lgr.WriteErr(errors.New("some error"))
+		Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+		content, err := os.ReadFile(lgr.filepath)
+		Testing.AssertNotError(t, err)
+		lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+		Testing.AssertEqual(t, 501, len(lines))
+		Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : message"))
+		Testing.AssertTrue(t, strings.HasSuffix(lines[500], " : Error: some error"))
+	}
+}
+
+func Test_fileLoggerLifecycle(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	lgr.Write("first")
+	Testing.AssertNotError(t, lgr.Flush(context.Background()))
+	content, _ := os.ReadFile(lgr.filepath)
+	Testing.AssertTrue(t, strings.HasSuffix(string(content), " : first\n"))
+
+	lgr.StopLogger()
+	lgr.StopLogger()
+	Testing.AssertNotPanic(t, func() {
+		lgr.Write("after stop")
+	})
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertEqual(t, ErrLoggerClosed, lgr.Flush(context.Background()))
+	Testing.AssertEqual(t, uint64(1), lgr.DroppedMessages())
+	Testing.AssertError(t, lgr.logFile.Close())
+}
+
+func Test_fileLoggerCloseWithoutStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	t.Setenv("LOGFILE_GO_LOGGER", path)
+	lgr := &FileLoggerImpl{}
+	lgr.init()
+	lgr.Write("queued")
+	Testing.AssertEqual(t, ErrLoggerNotStarted, lgr.Flush(context.Background()))
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	content, _ := os.ReadFile(path)
+	Testing.AssertTrue(t, strings.HasSuffix(string(content), " : queued\n"))
+}
+
+func Test_independentFileLoggers(t *testing.T) {
+	first := newTestFileLogger(t, FsyncAlways)
+	second := newTestFileLogger(t, FsyncAlways)
+	first.Write("to first")
+	second.Write("to second")
+	Testing.AssertNotError(t, first.Close(context.Background()))
+	Testing.AssertNotError(t, second.Close(context.Background()))
+
+	content, _ := os.ReadFile(first.filepath)
+	Testing.AssertTrue(t, strings.HasSuffix(string(content), " : to first\n"))
+	content, _ = os.ReadFile(second.filepath)
+	Testing.AssertTrue(t, strings.HasSuffix(string(content), " : to second\n"))
+
+	_, err := NewFileLogger(FileLoggerOptions{Path: filepath.Join(t.TempDir(), "missing", "log")})
+	Testing.AssertError(t, err)
+}
+
+func benchmarkFileLogger(b *testing.B, policy FsyncPolicy) {
+	lgr := newTestFileLogger(b, policy)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lgr.Write("benchmark message with a bit of payload to make it realistic")
+	}
+	lgr.Close(context.Background())
+}
+
+func BenchmarkFileLoggerFsyncAlways(b *testing.B) {
+	benchmarkFileLogger(b, FsyncAlways)
+}
+
+func BenchmarkFileLoggerFsyncInterval(b *testing.B) {
+	benchmarkFileLogger(b, FsyncInterval)
+}
+
+func BenchmarkFileLoggerFsyncOnError(b *testing.B) {
+	benchmarkFileLogger(b, FsyncOnError)
+}
+
+func BenchmarkFileLoggerFsyncNever(b *testing.B) {
+	benchmarkFileLogger(b, FsyncNever)
+}
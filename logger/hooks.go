@@ -0,0 +1,63 @@
+package Logger
+
+import "os"
+
+// Runs on every entry before it is redacted and handed to the sink, it may modify the entry.
+// Returning false drops the entry. Hooks run on the goroutine that logs, so they should be quick.
+type Hook func(e *Entry) bool
+
+type hook struct {
+	fn     Hook
+	levels uint8 // bit set of the levels the hook runs for, allLevels if it runs for every level
+}
+
+const allLevels = 0xff
+
+func (h hook) runsFor(level Level) bool {
+	return h.levels == allLevels || (level >= 0 && level < 8 && h.levels&(1<<level) != 0)
+}
+
+// Registers h for the given levels (every level if none is given), hooks run in the order they were added.
+// Call it before the logger is used.
+func (c *core) AddHook(h Hook, levels ...Level) {
+	mask := uint8(allLevels)
+	if len(levels) > 0 {
+		mask = 0
+		for _, level := range levels {
+			if level >= 0 && level < 8 {
+				mask |= 1 << level
+			}
+		}
+	}
+	c.hooks = append(c.hooks, hook{fn: h, levels: mask})
+}
+
+// Appends fields to every entry
+func StaticFields(fields ...Field) Hook {
+	return func(e *Entry) bool {
+		e.Fields = append(e.Fields, fields...)
+		return true
+	}
+}
+
+// hostname, pid and version (if it isn't empty) fields for StaticFields
+func ProcessFields(version string) []Field {
+	fields := []Field{{Key: "pid", Value: os.Getpid()}}
+	if hostname, err := os.Hostname(); err == nil {
+		fields = append([]Field{{Key: "hostname", Value: hostname}}, fields...)
+	}
+	if version != "" {
+		fields = append(fields, Field{Key: "version", Value: version})
+	}
+	return fields
+}
+
+// Calls f with a copy of every Error level entry, e.g. to alert or to count failures
+func OnError(f func(e Entry)) Hook {
+	return func(e *Entry) bool {
+		if e.Level >= LevelError {
+			f(*e)
+		}
+		return true
+	}
+}
@@ -0,0 +1,52 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_hooks(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	var alerts []string
+	lgr.AddHook(func(e *Entry) bool {
+		return !strings.Contains(e.Message, "healthcheck")
+	})
+	lgr.AddHook(func(e *Entry) bool {
+		e.Message = "[warn] " + e.Message
+		return true
+	}, LevelWarn)
+	lgr.AddHook(StaticFields(ProcessFields("1.2.3")...))
+	lgr.AddHook(OnError(func(e Entry) {
+		alerts = append(alerts, e.text())
+	}))
+
+	lgr.Write("GET /healthcheck")
+	lgr.Write("hello")
+	lgr.WarnCtx(context.Background(), "careful")
+	lgr.WriteErr(errors.New("boom"))
+
+	Testing.AssertEqual(t, "hello,[warn] careful,Error: boom", memoryMessages(lgr.Entries()))
+	Testing.AssertEqual(t, "Error: boom", strings.Join(alerts, ","))
+	Testing.AssertEqual(t, uint64(1), lgr.Stats().Messages["info"])
+	line := lgr.Entries()[0].line()
+	hostname, _ := os.Hostname()
+	Testing.AssertTrue(t, strings.Contains(line, " hostname="+hostname+" pid="))
+	Testing.AssertTrue(t, strings.HasSuffix(line, " version=1.2.3\n"))
+}
+
+func Test_hooksOfMultiLoggerSinks(t *testing.T) {
+	first, second := NewMemoryLogger(10), NewMemoryLogger(10)
+	first.AddHook(StaticFields(F("sink", "first")))
+	second.AddHook(StaticFields(F("sink", "second")))
+	lgr := NewMultiLogger(MultiSink{Logger: first}, MultiSink{Logger: second})
+	lgr.AddHook(StaticFields(F("app", "test")))
+	lgr.Write("hello")
+
+	Testing.AssertTrue(t, strings.HasSuffix(first.Entries()[0].line(), " : hello app=test sink=first\n"))
+	Testing.AssertTrue(t, strings.HasSuffix(second.Entries()[0].line(), " : hello app=test sink=second\n"))
+}
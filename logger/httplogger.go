@@ -0,0 +1,335 @@
+package Logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	defaultHTTPBatchSize   = 100
+	defaultHTTPTimeout     = 10 * time.Second
+	defaultMaxSpoolBytes   = 64 << 20
+	spoolRetryTickInterval = 100 * time.Millisecond
+)
+
+// Options of NewHTTPLogger, URL is required
+type HTTPLoggerOptions struct {
+	URL           string       // every batch is POSTed to it as a JSON array
+	Client        *http.Client // defaults to a client with a 10s timeout
+	Header        http.Header  // added to every request, e.g. Authorization
+	BatchSize     int          // maximum number of entries per request, defaults to 100
+	SpoolPath     string       // file holding the entries while the collector is unreachable, without it they are dropped
+	MaxSpoolBytes int64        // entries that don't fit in the spool are dropped, defaults to 64MiB
+	BufferSize    int32        // size of the channel, defaults to Logbuffersize
+	Overflow      OverflowPolicy
+	SampleRate    int // used by OverflowSample
+}
+
+// The JSON form of an Entry sent to the collector
+type entryRecord struct {
+	Time      time.Time                  `json:"time"`
+	Level     string                     `json:"level"`
+	RequestID string                     `json:"request_id,omitempty"`
+	Message   string                     `json:"message,omitempty"`
+	Error     string                     `json:"error,omitempty"`
+	Fields    map[string]json.RawMessage `json:"fields,omitempty"`
+	Caller    string                     `json:"caller,omitempty"`
+	Stack     string                     `json:"stack,omitempty"`
+}
+
+// Returns a started logger shipping entries to opts.URL. Entries are collected from the channel into batches of at
+// most BatchSize, a failed request is retried with exponential backoff (100ms doubling up to 30s). Until it succeeds
+// new batches are appended to the spool, which is replayed in order once the collector is back, so delivery is
+// at least once. A spool left over from a previous run is replayed as well.
+// Responses with a 4xx status (except 408 and 429) are not retried, their batch is dropped.
+func NewHTTPLogger(opts HTTPLoggerOptions) (*HTTPLoggerImpl, error) {
+	lgr := &HTTPLoggerImpl{opts: opts}
+	lgr.SetOverflowPolicy(opts.Overflow, opts.SampleRate)
+	if err := lgr.setup(bufferSize(opts.BufferSize)); err != nil {
+		return nil, err
+	}
+	lgr.start(lgr.run)
+	return lgr, nil
+}
+
+// The URL is read from LOGURL_GO_LOGGER and the spool path from LOGSPOOL_GO_LOGGER if they are not set in the options
+func (lgr *HTTPLoggerImpl) init() {
+	if lgr.opts.URL == "" {
+		lgr.opts.URL = os.Getenv("LOGURL_GO_LOGGER")
+	}
+	if lgr.opts.SpoolPath == "" {
+		lgr.opts.SpoolPath = os.Getenv("LOGSPOOL_GO_LOGGER")
+	}
+	if err := lgr.setup(Logbuffersize); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (lgr *HTTPLoggerImpl) setup(size int32) error {
+	if lgr.opts.URL == "" {
+		return errors.New("HTTPLogger needs a URL")
+	}
+	if lgr.opts.BatchSize <= 0 {
+		lgr.opts.BatchSize = defaultHTTPBatchSize
+	}
+	if lgr.opts.MaxSpoolBytes <= 0 {
+		lgr.opts.MaxSpoolBytes = defaultMaxSpoolBytes
+	}
+	lgr.client = lgr.opts.Client
+	if lgr.client == nil {
+		lgr.client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	if lgr.opts.SpoolPath != "" {
+		spool, err := openSpool(lgr.opts.SpoolPath)
+		if err != nil {
+			return fmt.Errorf("Error opening or creating spool file: %w", err)
+		}
+		lgr.spool = spool
+	}
+	lgr.sink = lgr
+	lgr.initQueue(size)
+	return nil
+}
+
+func (logger *HTTPLoggerImpl) StartLogger() {
+	logger.start(logger.run)
+}
+
+func (logger *HTTPLoggerImpl) run() {
+	retry := time.NewTicker(spoolRetryTickInterval)
+	defer retry.Stop()
+	report := time.NewTicker(DropReportInterval)
+	defer report.Stop()
+
+	batch := make([][]byte, 0, logger.opts.BatchSize)
+	for {
+		select {
+		case msg, ok := <-logger.messages:
+			if !ok {
+				return
+			}
+			batch = append(batch[:0], msg.json())
+			open := true
+		drain:
+			for len(batch) < logger.opts.BatchSize {
+				select {
+				case msg, ok = <-logger.messages:
+					if !ok {
+						open = false
+						break drain
+					}
+					batch = append(batch, msg.json())
+				default:
+					break drain
+				}
+			}
+
+			logger.deliver(batch)
+			logger.markProcessed(len(batch))
+			if !open {
+				return
+			}
+		case <-retry.C:
+			logger.replay()
+		case <-report.C:
+			if msg, ok := logger.droppedReport(logger.now()); ok {
+				logger.deliver([][]byte{msg.json()})
+			}
+		}
+	}
+}
+
+// Posts the batch unless older entries are waiting in the spool or the backoff isn't over, then it goes to the spool
+func (logger *HTTPLoggerImpl) deliver(batch [][]byte) {
+	if logger.spool != nil && !logger.spool.empty() {
+		logger.toSpool(batch)
+		logger.replay()
+		return
+	}
+	if time.Now().Before(logger.retryAt) {
+		logger.toSpool(batch)
+		return
+	}
+	if retry, err := logger.post(batch); err != nil {
+		logger.failed(err)
+		if retry {
+			logger.toSpool(batch)
+		} else {
+			logger.dropped.Add(uint64(len(batch)))
+		}
+	}
+}
+
+// Sends the spooled entries in order until the spool is empty or a request fails
+func (logger *HTTPLoggerImpl) replay() {
+	for logger.spool != nil && !logger.spool.empty() && !time.Now().Before(logger.retryAt) {
+		records, n, err := logger.spool.next(logger.opts.BatchSize)
+		if err != nil {
+			logger.failed(err)
+			return
+		}
+		if n == 0 {
+			return
+		}
+		valid := records[:0]
+		for _, r := range records {
+			// A record cut short by a crash would make the collector reject the whole batch
+			if json.Valid(r) {
+				valid = append(valid, r)
+			} else {
+				logger.dropped.Add(1)
+			}
+		}
+		if len(valid) > 0 {
+			retry, err := logger.post(valid)
+			if err != nil {
+				logger.failed(err)
+				if retry {
+					return
+				}
+				logger.dropped.Add(uint64(len(valid)))
+			}
+		}
+		if err := logger.spool.advance(n); err != nil {
+			logger.failed(err)
+			return
+		}
+	}
+}
+
+func (logger *HTTPLoggerImpl) toSpool(batch [][]byte) {
+	if logger.spool == nil {
+		logger.dropped.Add(uint64(len(batch)))
+		return
+	}
+	size := 0
+	for _, r := range batch {
+		size += len(r) + 1
+	}
+	if logger.spool.size+int64(size) > logger.opts.MaxSpoolBytes {
+		logger.dropped.Add(uint64(len(batch)))
+		return
+	}
+	if err := logger.spool.append(batch); err != nil {
+		logger.dropped.Add(uint64(len(batch)))
+		logger.failed(err)
+	}
+}
+
+// retry reports whether sending the same batch again could succeed
+func (logger *HTTPLoggerImpl) post(batch [][]byte) (retry bool, err error) {
+	body := bytes.NewBuffer(make([]byte, 0, 64*len(batch)))
+	body.WriteByte('[')
+	for i, r := range batch {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(r)
+	}
+	body.WriteByte(']')
+
+	req, err := http.NewRequest(http.MethodPost, logger.opts.URL, body)
+	if err != nil {
+		return false, err
+	}
+	for key, values := range logger.opts.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	size := body.Len()
+	start := time.Now()
+	resp, err := logger.client.Do(req)
+	if err != nil {
+		logger.metrics.observeWrite(0, time.Since(start), nil)
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		logger.metrics.observeWrite(size, time.Since(start), nil)
+		logger.backoff = 0
+		logger.retryAt = time.Time{}
+		return false, nil
+	}
+	logger.metrics.observeWrite(0, time.Since(start), nil)
+	err = fmt.Errorf("collector responded with %s", resp.Status)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return false, err
+	}
+	return true, err
+}
+
+// Records the error and pushes the next attempt out
+func (logger *HTTPLoggerImpl) failed(err error) {
+	logger.backoff = min(max(logger.backoff*2, minReconnectBackoff), maxReconnectBackoff)
+	logger.retryAt = time.Now().Add(logger.backoff)
+	logger.metrics.writeFailed(err)
+}
+
+// Safe to call more than once
+func (logger *HTTPLoggerImpl) StopLogger() {
+	logger.stop()
+}
+
+// Waits until the messages written so far were sent, spooled or dropped
+func (logger *HTTPLoggerImpl) Flush(ctx context.Context) error {
+	return logger.flush(ctx)
+}
+
+// Stops the logger, sends or spools the queued messages and closes the spool.
+// Entries still in the spool are replayed by the next logger using the same SpoolPath.
+func (logger *HTTPLoggerImpl) Close(ctx context.Context) error {
+	return logger.close(ctx, logger.run, func() error {
+		if logger.spool == nil {
+			return nil
+		}
+		return logger.spool.close()
+	})
+}
+
+func (logger *HTTPLoggerImpl) log(e *Entry) {
+	logger.push(e)
+}
+
+func (e *Entry) record() entryRecord {
+	rec := entryRecord{
+		Time:      e.Time,
+		Level:     e.Level.String(),
+		RequestID: e.RequestID,
+		Message:   e.Message,
+		Stack:     e.Stack,
+	}
+	if e.Err != nil {
+		rec.Error = e.Err.Error()
+	}
+	if e.Caller != nil {
+		rec.Caller = e.Caller.String()
+	}
+	if len(e.Fields) > 0 {
+		rec.Fields = make(map[string]json.RawMessage, len(e.Fields))
+		for _, f := range e.Fields {
+			v, err := json.Marshal(f.Value)
+			if err != nil {
+				v, _ = json.Marshal(fmt.Sprint(f.Value))
+			}
+			rec.Fields[f.Key] = v
+		}
+	}
+	return rec
+}
+
+// The entry as a single line of JSON
+func (e *Entry) json() []byte {
+	b, err := json.Marshal(e.record())
+	if err != nil {
+		b, _ = json.Marshal(entryRecord{Time: e.Time, Level: e.Level.String(), Message: e.text()})
+	}
+	return b
+}
@@ -0,0 +1,142 @@
+package Logger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+// A collector that answers with status and records the messages of the accepted batches
+type testCollector struct {
+	*httptest.Server
+	status   atomic.Int32
+	mutex    sync.Mutex
+	messages []string
+	batches  int
+}
+
+func newTestCollector(t *testing.T) *testCollector {
+	c := &testCollector{}
+	c.status.Store(http.StatusOK)
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := int(c.status.Load())
+		if status == http.StatusOK {
+			var batch []entryRecord
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				status = http.StatusBadRequest
+			}
+			c.mutex.Lock()
+			c.batches++
+			for _, rec := range batch {
+				c.messages = append(c.messages, rec.Message)
+			}
+			c.mutex.Unlock()
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *testCollector) received() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return strings.Join(c.messages, ",")
+}
+
+func (c *testCollector) waitFor(t *testing.T, messages string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.received() != messages && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	Testing.AssertEqual(t, messages, c.received())
+}
+
+func Test_httpLoggerBatching(t *testing.T) {
+	collector := newTestCollector(t)
+	// Not started, so the queued entries go out in full batches on Close
+	lgr := &HTTPLoggerImpl{opts: HTTPLoggerOptions{URL: collector.URL, BatchSize: 10}}
+	lgr.init()
+	var want []string
+	for i := 0; i < 25; i++ {
+		want = append(want, "message"+strings.Repeat("!", i%3))
+	}
+	for _, msg := range want {
+		lgr.Write(msg)
+	}
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertEqual(t, strings.Join(want, ","), collector.received())
+	Testing.AssertEqual(t, 3, collector.batches)
+	Testing.AssertNotError(t, lgr.LastError())
+}
+
+func Test_httpLoggerSpool(t *testing.T) {
+	collector := newTestCollector(t)
+	collector.status.Store(http.StatusServiceUnavailable)
+	spool := filepath.Join(t.TempDir(), "spool")
+	lgr, err := NewHTTPLogger(HTTPLoggerOptions{URL: collector.URL, SpoolPath: spool})
+	Testing.AssertNotError(t, err)
+
+	lgr.Write("a")
+	lgr.Write("b")
+	Testing.AssertNotError(t, lgr.Flush(context.Background()))
+	Testing.AssertError(t, lgr.LastError())
+	content, _ := os.ReadFile(spool)
+	Testing.AssertEqual(t, 2, strings.Count(string(content), "\n"))
+
+	collector.status.Store(http.StatusOK)
+	lgr.Write("c")
+	collector.waitFor(t, "a,b,c")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	content, _ = os.ReadFile(spool)
+	Testing.AssertEqual(t, "", string(content))
+	Testing.AssertEqual(t, uint64(0), lgr.DroppedMessages())
+}
+
+func Test_httpLoggerSpoolReplayedAfterRestart(t *testing.T) {
+	collector := newTestCollector(t)
+	collector.status.Store(http.StatusBadGateway)
+	spool := filepath.Join(t.TempDir(), "spool")
+	lgr, err := NewHTTPLogger(HTTPLoggerOptions{URL: collector.URL, SpoolPath: spool})
+	Testing.AssertNotError(t, err)
+	lgr.Write("before restart")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	// A record cut short by a crash is skipped
+	f, _ := os.OpenFile(spool, os.O_APPEND|os.O_WRONLY, 0660)
+	f.WriteString(`{"message":"cut`)
+	f.Close()
+
+	collector.status.Store(http.StatusOK)
+	lgr, err = NewHTTPLogger(HTTPLoggerOptions{URL: collector.URL, SpoolPath: spool})
+	Testing.AssertNotError(t, err)
+	lgr.Write("after restart")
+	collector.waitFor(t, "before restart,after restart")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertEqual(t, uint64(1), lgr.DroppedMessages())
+}
+
+func Test_httpLoggerRejectedBatch(t *testing.T) {
+	collector := newTestCollector(t)
+	collector.status.Store(http.StatusBadRequest)
+	lgr, err := NewHTTPLogger(HTTPLoggerOptions{URL: collector.URL, SpoolPath: filepath.Join(t.TempDir(), "spool")})
+	Testing.AssertNotError(t, err)
+	lgr.Write("rejected")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	Testing.AssertEqual(t, uint64(1), lgr.DroppedMessages())
+	Testing.AssertTrue(t, strings.Contains(lgr.LastError().Error(), "400"))
+
+	_, err = NewHTTPLogger(HTTPLoggerOptions{})
+	Testing.AssertError(t, err)
+}
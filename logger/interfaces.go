@@ -1,5 +1,12 @@
 package Logger
 
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
 // Message format(s)
 //
 // Unixdate : message\n
@@ -9,6 +16,20 @@ package Logger
 // Unixdate : uuid : message\n
 //
 // Unixdate : uuid : Error: error\n
+//
+// Unixdate : uuid message: Error: error\n
+//
+// The timestamp format, location and clock can be changed with SetTimeFormat, SetLocation and SetClock.
+//
+// Lifecycle of the buffered loggers (Console, File):
+//
+//	created --StartLogger--> running --StopLogger--> stopped --Close--> closed
+//
+// Messages written before StartLogger wait in the channel, messages written after StopLogger
+// or Close (or still waiting for room when it is called) are dropped and counted in
+// DroppedMessages. StopLogger and Close can be called any number of times, Close also works
+// on a logger that was never started (it drains the channel itself). Close gives up when its
+// ctx expires. Flush on a closed logger returns ErrLoggerClosed.
 type Logger interface {
 	ReleaseLogger
 	DebugLogger
@@ -18,9 +39,26 @@ type (
 	ReleaseLogger interface {
 		// Private, use it for member initialization etc
 		init()
+		// Private, hands an already built entry to the logger (used to forward entries between loggers)
+		logEntry(e *Entry)
+		// Private, writes an entry synchronously after flushing the queue (used by LogPanic)
+		crash(e *Entry)
+		// Private, build and log an entry, functions logging on behalf of their caller (LogResult etc.) call them directly
+		emit(level Level, message string, err error, uuid string, fields ...Field)
+		emitCtx(ctx context.Context, level Level, message string, err error)
+		// Private, the current time from the clock of the logger (see SetClock and SetLocation)
+		now() time.Time
+		// Private, whether caller capture is on (see SetCallerCapture)
+		capturesCaller() bool
 		// Start an infinite loop to write out messages from the channel
 		StartLogger()
 		StopLogger()
+		// Waits until every message written before the call reached the sink
+		Flush(ctx context.Context) error
+		// Stops the logger, waits until the queued messages are written and releases its resources
+		Close(ctx context.Context) error
+		// Counters, queue depth and write latency of the logger
+		Stats() Stats
 		Write(message string)
 		WriteRequest(message string, uuid string)
 		// If an error that is not nill passed in it logs the error and returns 1, otherwise 0
@@ -28,6 +66,19 @@ type (
 		WriteErrRequest(err error, uuid string) int
 
 		WriteErrMsgRequest(err error, message string, uuid string) int
+
+		// The *Ctx methods pick up the request id, trace ids and fields attached to ctx (see WithRequestID, WithTrace, WithFields)
+		InfoCtx(ctx context.Context, message string)
+		WarnCtx(ctx context.Context, message string)
+		ErrorCtx(ctx context.Context, err error) int
+		ErrorMsgCtx(ctx context.Context, err error, message string) int
+
+		// Runs h on the entries of the given levels before they reach the sink, see Hook
+		AddHook(h Hook, levels ...Level)
+
+		// Timed operations, End / EndErr log the duration and the outcome (see Span)
+		Span(name string, fields ...Field) *Span
+		SpanRequest(name string, uuid string, fields ...Field) *Span
 	}
 	// Use _DEBUG prints to strip them out of release builds
 	DebugLogger interface {
@@ -42,6 +93,8 @@ type (
 		WriteErrRequestDebug(err error, uuid string) int
 
 		WriteErrMsgRequestDebug(err error, message string, uuid string) int
+
+		DebugCtx(ctx context.Context, message string)
 	}
 )
 
@@ -51,4 +104,14 @@ var (
 	_ Logger = (*ConsoleLoggerImpl)(nil)
 	_ Logger = (*FileLoggerImpl)(nil)
 	_ Logger = (*SlogLoggerImpl)(nil)
+	_ Logger = (*MultiLogger)(nil)
+	_ Logger = (*SyslogLoggerImpl)(nil)
+	_ Logger = (*JournaldLoggerImpl)(nil)
+	_ Logger = (*HTTPLoggerImpl)(nil)
+	_ Logger = (*MemoryLoggerImpl)(nil)
+	_ Logger = (*AuditLoggerImpl)(nil)
+	_ Logger = (*Dispatcher)(nil)
+
+	_ slog.Handler = (*SlogHandler)(nil)
+	_ http.Handler = (*MemoryLoggerImpl)(nil)
 )
@@ -0,0 +1,95 @@
+package Logger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Options of NewJournaldLogger, the zero value is usable
+type JournaldOptions struct {
+	Socket     string // defaults to /run/systemd/journal/socket
+	Identifier string // SYSLOG_IDENTIFIER, defaults to the name of the executable
+	BufferSize int32  // size of the channel, defaults to Logbuffersize
+	Overflow   OverflowPolicy
+	SampleRate int // used by OverflowSample
+}
+
+// Returns a started logger using the native journal protocol, fields become journal fields
+// (upper cased, invalid characters replaced by _). Every entry is one datagram, so it is limited by the socket buffer size.
+func NewJournaldLogger(opts JournaldOptions) *JournaldLoggerImpl {
+	lgr := &JournaldLoggerImpl{opts: opts}
+	lgr.SetOverflowPolicy(opts.Overflow, opts.SampleRate)
+	lgr.setup(bufferSize(opts.BufferSize))
+	lgr.start(lgr.run)
+	return lgr
+}
+
+func (lgr *JournaldLoggerImpl) init() {
+	lgr.setup(Logbuffersize)
+}
+
+func (lgr *JournaldLoggerImpl) setup(size int32) {
+	if lgr.opts.Socket == "" {
+		lgr.opts.Socket = "/run/systemd/journal/socket"
+	}
+	if lgr.opts.Identifier == "" {
+		lgr.opts.Identifier = filepath.Base(os.Args[0])
+	}
+	lgr.initSocket("unixgram", lgr.opts.Socket, size, lgr.format)
+}
+
+func (lgr *JournaldLoggerImpl) format(e *Entry) []byte {
+	var b []byte
+	b = appendJournalField(b, "MESSAGE", e.text())
+	b = appendJournalField(b, "PRIORITY", strconv.Itoa(syslogSeverity(e.Level)))
+	b = appendJournalField(b, "SYSLOG_IDENTIFIER", lgr.opts.Identifier)
+	if e.RequestID != "" {
+		b = appendJournalField(b, "REQUEST_ID", e.RequestID)
+	}
+	if e.Caller != nil {
+		b = appendJournalField(b, "CODE_FILE", e.Caller.File)
+		b = appendJournalField(b, "CODE_LINE", strconv.Itoa(e.Caller.Line))
+		b = appendJournalField(b, "CODE_FUNC", e.Caller.Function)
+	}
+	if e.Stack != "" {
+		b = appendJournalField(b, "STACK", e.Stack)
+	}
+	for _, f := range e.Fields {
+		b = appendJournalField(b, journalFieldName(f.Key), fmt.Sprint(f.Value))
+	}
+	return b
+}
+
+// KEY=value\n, or KEY\n<little endian uint64 length>value\n if the value has a newline in it
+func appendJournalField(b []byte, name string, value string) []byte {
+	b = append(b, name...)
+	if !strings.ContainsRune(value, '\n') {
+		b = append(b, '=')
+		b = append(b, value...)
+		return append(b, '\n')
+	}
+	b = append(b, '\n')
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// Journal field names are upper case letters, digits and underscores, they can't start with an underscore or a digit
+func journalFieldName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	name := strings.TrimLeft(b.String(), "_0123456789")
+	if name == "" {
+		return "FIELD"
+	}
+	return name
+}
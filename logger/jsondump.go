@@ -0,0 +1,354 @@
+package Logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const truncated = "[TRUNCATED]"
+
+// Options of DumpJSON, WriteJSON and JSONField, the zero value gives compact JSON redacted by DefaultRedactor
+type DumpOptions struct {
+	Indent     string    // indentation of nested values, empty for compact output
+	MaxDepth   int       // objects and arrays nested deeper are replaced by "[TRUNCATED]", 0 means no limit
+	MaxSize    int       // output beyond MaxSize bytes is cut off and marked with ...[TRUNCATED], 0 means no limit
+	EscapeHTML bool      // escape <, > and & in strings like json.Marshal does
+	Redactor   *Redactor // defaults to DefaultRedactor, NewRedactor(nil) only honors the `log:"redact"` tags
+}
+
+// Writes v as JSON to w while walking it, the parts beyond MaxDepth are never visited and the walk stops once
+// MaxSize bytes were written. A value cut short by MaxSize isn't valid JSON anymore, after an encoding error
+// w holds the part written before it. A cycle is an error like for json.Marshal.
+func WriteJSON(w io.Writer, v any, opts DumpOptions) error {
+	d := &jsonDumper{r: opts.Redactor, indent: opts.Indent, escapeHTML: opts.EscapeHTML, path: map[visit]struct{}{}}
+	if d.r == nil {
+		d.r = DefaultRedactor
+	}
+	buffered := bufio.NewWriter(w)
+	d.w = buffered
+	if opts.MaxSize > 0 {
+		d.limit = &limitWriter{w: buffered, remaining: opts.MaxSize}
+		d.w = d.limit
+	}
+	depth := opts.MaxDepth
+	if depth <= 0 {
+		depth = -1
+	}
+
+	d.value(reflect.ValueOf(v), depth, 0)
+	d.write("\n")
+	if d.err != nil {
+		buffered.Flush()
+		return fmt.Errorf("encoding %s as JSON: %w", TypeName(v), d.err)
+	}
+	if d.limit != nil && d.limit.cut {
+		buffered.WriteString("..." + truncated)
+	}
+	return buffered.Flush()
+}
+
+// Returns v as JSON without the trailing newline
+func DumpJSON(v any, opts DumpOptions) (string, error) {
+	var b strings.Builder
+	if err := WriteJSON(&b, v, opts); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// A field whose value is only turned into JSON when a sink formats the entry, v must not be modified after it was logged.
+// Text sinks print the JSON, JSON sinks embed it as a value. An encoding error is printed in place of the value.
+func JSONField(key string, v any, opts DumpOptions) Field {
+	return Field{Key: key, Value: lazyJSON{v: v, opts: opts}}
+}
+
+type lazyJSON struct {
+	v    any
+	opts DumpOptions
+}
+
+func (l lazyJSON) String() string {
+	s, err := DumpJSON(l.v, l.opts)
+	if err != nil {
+		return "!(" + err.Error() + ")"
+	}
+	return s
+}
+
+func (l lazyJSON) MarshalJSON() ([]byte, error) {
+	opts := l.opts
+	// The result has to stay valid JSON inside the entry
+	opts.MaxSize = 0
+	s, err := DumpJSON(l.v, opts)
+	if err != nil {
+		return json.Marshal("!(" + err.Error() + ")")
+	}
+	return []byte(s), nil
+}
+
+// A package qualifier in the type arguments of generic types, reflect names the package by its import path there
+// (github.com/a/b.T) and by its name everywhere else
+var importPathPattern = regexp.MustCompile(`((?:[\w.\-]+/)*)([\w\-]+)\.`)
+
+// The name of the dynamic type of v: credentials, *credentials, pair[int,Logger.credentials], []*Logger.credentials,
+// struct { A int }. nil gives "nil". A type argument from a package none of the types reachable from v belongs to
+// is qualified by the last element of its import path.
+func TypeName(v any) string {
+	if v == nil {
+		return "nil"
+	}
+	return typeName(reflect.TypeOf(v))
+}
+
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer && t.Name() == "" {
+		return "*" + typeName(t.Elem())
+	}
+	name := t.Name()
+	if name == "" {
+		name = t.String()
+	}
+	if !strings.Contains(name, "/") {
+		return name
+	}
+	names := map[string]string{}
+	packageNames(t, names, map[reflect.Type]bool{})
+	return importPathPattern.ReplaceAllStringFunc(name, func(qualifier string) string {
+		m := importPathPattern.FindStringSubmatch(qualifier)
+		if pkg, ok := names[m[1]+m[2]]; ok {
+			return pkg + "."
+		}
+		return m[2] + "."
+	})
+}
+
+// Collects the package names by import path of the named types reachable from t (elements, fields and method signatures)
+func packageNames(t reflect.Type, names map[string]string, seen map[reflect.Type]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	if t.PkgPath() != "" && t.Name() != "" {
+		if pkg, _, ok := strings.Cut(t.String(), "."); ok {
+			names[t.PkgPath()] = pkg
+		}
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan:
+		packageNames(t.Elem(), names, seen)
+	case reflect.Map:
+		packageNames(t.Key(), names, seen)
+		packageNames(t.Elem(), names, seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			packageNames(t.Field(i).Type, names, seen)
+		}
+	case reflect.Func:
+		for i := 0; i < t.NumIn(); i++ {
+			packageNames(t.In(i), names, seen)
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			packageNames(t.Out(i), names, seen)
+		}
+	}
+	for i := 0; i < t.NumMethod(); i++ {
+		packageNames(t.Method(i).Type, names, seen)
+	}
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface && t.Name() != "" {
+		packageNames(reflect.PointerTo(t), names, seen)
+	}
+}
+
+// Writes the JSON of a value while walking it like Redactor.Value does, so the limits bound the work and not only the output
+type jsonDumper struct {
+	r          *Redactor
+	indent     string
+	escapeHTML bool
+	w          io.Writer
+	limit      *limitWriter       // nil without MaxSize
+	path       map[visit]struct{} // pointers, maps and slices being written, see enter
+	err        error
+}
+
+// True once the walk should stop: writing failed or MaxSize was reached
+func (d *jsonDumper) done() bool {
+	return d.err != nil || (d.limit != nil && d.limit.cut)
+}
+
+func (d *jsonDumper) write(s string) {
+	if d.done() {
+		return
+	}
+	_, d.err = io.WriteString(d.w, s)
+}
+
+func (d *jsonDumper) newline(level int) {
+	if d.indent != "" {
+		d.write("\n" + strings.Repeat(d.indent, level))
+	}
+}
+
+// Writes v at nesting level, depth is the number of objects and arrays that may still be opened (negative for no limit)
+func (d *jsonDumper) value(v reflect.Value, depth int, level int) {
+	if d.done() {
+		return
+	}
+	if !v.IsValid() {
+		d.write("null")
+		return
+	}
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		d.leaf(v.Interface(), level)
+		return
+	}
+	leave, ok := enter(d.path, v)
+	if !ok {
+		d.err = cycleError(v)
+		return
+	}
+	defer leave()
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			d.write("null")
+			return
+		}
+		d.value(v.Elem(), depth, level)
+	case reflect.Struct:
+		if depth == 0 {
+			d.leaf(truncated, level)
+			return
+		}
+		d.write("{")
+		n := 0
+		d.r.structFields(v, func(name string, fv reflect.Value, redact bool) bool {
+			d.key(n, name, level)
+			if redact {
+				d.leaf(redacted, level+1)
+			} else {
+				d.value(fv, depth-1, level+1)
+			}
+			n++
+			return !d.done()
+		})
+		d.end("}", n, level)
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			d.leaf(v.Interface(), level)
+			return
+		}
+		if depth == 0 {
+			d.leaf(truncated, level)
+			return
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+		d.write("{")
+		for i, key := range keys {
+			if d.done() {
+				return
+			}
+			d.key(i, key.String(), level)
+			if d.r.isKey(key.String()) {
+				d.leaf(redacted, level+1)
+			} else {
+				d.value(v.MapIndex(key), depth-1, level+1)
+			}
+		}
+		d.end("}", len(keys), level)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			d.leaf(v.Interface(), level)
+			return
+		}
+		if depth == 0 {
+			d.leaf(truncated, level)
+			return
+		}
+		d.write("[")
+		for i := 0; i < v.Len(); i++ {
+			if d.done() {
+				return
+			}
+			if i > 0 {
+				d.write(",")
+			}
+			d.newline(level + 1)
+			d.value(v.Index(i), depth-1, level+1)
+		}
+		d.end("]", v.Len(), level)
+	case reflect.String:
+		d.leaf(d.r.String(v.String()), level)
+	default:
+		d.leaf(v.Interface(), level)
+	}
+}
+
+// Writes the key of the i-th member of an object
+func (d *jsonDumper) key(i int, key string, level int) {
+	if i > 0 {
+		d.write(",")
+	}
+	d.newline(level + 1)
+	d.leaf(key, level+1)
+	if d.indent == "" {
+		d.write(":")
+	} else {
+		d.write(": ")
+	}
+}
+
+// Closes an object or array with n members
+func (d *jsonDumper) end(bracket string, n int, level int) {
+	if n > 0 {
+		d.newline(level)
+	}
+	d.write(bracket)
+}
+
+// Writes a value encoding/json handles on its own (scalars, marshalers, maps without string keys)
+func (d *jsonDumper) leaf(v any, level int) {
+	if d.done() {
+		return
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(d.escapeHTML)
+	if d.indent != "" {
+		enc.SetIndent(strings.Repeat(d.indent, level), d.indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		d.err = err
+		return
+	}
+	d.write(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// Passes the first remaining bytes through and swallows the rest
+type limitWriter struct {
+	w         io.Writer
+	remaining int
+	cut       bool
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > l.remaining {
+		p = p[:l.remaining]
+		l.cut = true
+	}
+	l.remaining -= len(p)
+	if len(p) > 0 {
+		if _, err := l.w.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,130 @@
+package Logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+	Type "github.com/lbatuska/goutils/type"
+)
+
+type pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type tree struct {
+	Name     string `json:"name"`
+	Children []tree `json:"children,omitempty"`
+}
+
+func Test_typeName(t *testing.T) {
+	Testing.AssertEqual(t, "credentials", TypeName(credentials{}))
+	Testing.AssertEqual(t, "*credentials", TypeName(&credentials{}))
+	Testing.AssertEqual(t, "pair[string,Logger.credentials]", TypeName(pair[string, credentials]{}))
+	Testing.AssertEqual(t, "pair[string,Type.Optional[int]]", TypeName(pair[string, Type.Optional[int]]{}))
+	Testing.AssertEqual(t, "[]Logger.pair[Logger.DumpOptions,Logger.credentials]", TypeName([]pair[DumpOptions, credentials]{}))
+	Testing.AssertEqual(t, "[]*Logger.credentials", TypeName([]*credentials{}))
+	Testing.AssertEqual(t, "struct { A int }", TypeName(struct{ A int }{}))
+	Testing.AssertEqual(t, "nil", TypeName(nil))
+
+	p := pair[int, string]{Key: 1, Value: "one"}
+	Testing.AssertEqual(t, "pair[int,string]:\n{\n     \"key\": 1,\n     \"value\": \"one\"\n}\n", PrintJson(&p))
+	var v any = &p
+	Testing.AssertTrue(t, strings.HasPrefix(PrintJson(&v), "*pair[int,string]:\n"))
+
+	// Like json.MarshalIndent, HTML is escaped
+	html := pair[string, []int]{Key: "<b>&</b>", Value: []int{}}
+	expected, _ := json.MarshalIndent(html, "", "     ")
+	Testing.AssertEqual(t, "pair[string,[]int]:\n"+string(expected)+"\n", PrintJson(&html))
+}
+
+func Test_dumpJSON(t *testing.T) {
+	c := credentials{User: "john", Password: "hunter2"}
+	s, err := DumpJSON(c, DumpOptions{})
+	Testing.AssertNotError(t, err)
+	Testing.AssertEqual(t, `{"user":"john","password":"[REDACTED]","pin":"[REDACTED]","Extra":null}`, s)
+
+	s, _ = DumpJSON(map[string]string{"password": "x"}, DumpOptions{Redactor: NewRedactor(nil)})
+	Testing.AssertEqual(t, `{"password":"x"}`, s)
+
+	deep := tree{Name: "a", Children: []tree{{Name: "b", Children: []tree{{Name: "c"}}}}}
+	s, _ = DumpJSON(deep, DumpOptions{MaxDepth: 3})
+	Testing.AssertEqual(t, `{"name":"a","children":[{"name":"b","children":"[TRUNCATED]"}]}`, s)
+
+	var b bytes.Buffer
+	Testing.AssertNotError(t, WriteJSON(&b, strings.Repeat("x", 100), DumpOptions{MaxSize: 10}))
+	Testing.AssertEqual(t, `"xxxxxxxxx...[TRUNCATED]`, b.String())
+
+	s, _ = DumpJSON(pair[string, map[string]any]{Key: "<a>", Value: map[string]any{"b": []int{1, 2}, "a": struct{}{}, "token": 1}}, DumpOptions{Indent: "  "})
+	Testing.AssertEqual(t, "{\n  \"key\": \"<a>\",\n  \"value\": {\n    \"a\": {},\n    \"b\": [\n      1,\n      2\n    ],\n    \"token\": \"[REDACTED]\"\n  }\n}", s)
+
+	_, err = DumpJSON(map[string]any{"ch": make(chan int)}, DumpOptions{})
+	Testing.AssertError(t, err)
+	Testing.AssertTrue(t, strings.Contains(err.Error(), "encoding map[string]interface {} as JSON: json: unsupported type: chan int"))
+}
+
+type countedMarshaler struct{ count *int }
+
+func (m countedMarshaler) MarshalJSON() ([]byte, error) {
+	*m.count++
+	return []byte(`"value"`), nil
+}
+
+func Test_writeJSONStopsAtMaxSize(t *testing.T) {
+	count := 0
+	values := make([]countedMarshaler, 1000)
+	for i := range values {
+		values[i] = countedMarshaler{&count}
+	}
+	var b bytes.Buffer
+	Testing.AssertNotError(t, WriteJSON(&b, values, DumpOptions{MaxSize: 20}))
+	Testing.AssertEqual(t, `["value","value","va...[TRUNCATED]`, b.String())
+	Testing.AssertEqual(t, 3, count)
+}
+
+func Test_dumpJSONCycle(t *testing.T) {
+	n := &node{Name: "a"}
+	n.Next = n
+	Testing.AssertEqual(t, "Error parsing json data: encoding *node as JSON: json: unsupported value: encountered a cycle via *Logger.node", PrintJson(&n))
+	_, err := DumpJSON(n, DumpOptions{})
+	var unsupported *json.UnsupportedValueError
+	Testing.AssertTrue(t, errors.As(err, &unsupported))
+
+	m := map[string]any{}
+	m["self"] = m
+	_, err = DumpJSON(m, DumpOptions{})
+	Testing.AssertTrue(t, errors.As(err, &unsupported))
+
+	// The same value twice side by side isn't a cycle
+	shared := &node{Name: "b"}
+	out, err := DumpJSON([]*node{shared, {Name: "c", Next: shared}}, DumpOptions{})
+	Testing.AssertNotError(t, err)
+	Testing.AssertEqual(t, `[{"name":"b"},{"name":"c","next":{"name":"b"}}]`, out)
+
+	c := &chain{Name: "outer"}
+	c.chain = c
+	out, err = DumpJSON(c, DumpOptions{})
+	Testing.AssertNotError(t, err)
+	Testing.AssertEqual(t, `{"name":"outer"}`, out)
+}
+
+func Test_jsonField(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	p := &pair[string, int]{Key: "a", Value: 1}
+	lgr.InfoCtx(WithFields(context.Background(), JSONField("pair", p, DumpOptions{})), "dumped")
+	e := lgr.Entries()[0]
+	Testing.AssertTrue(t, strings.HasSuffix(e.line(), ` : dumped pair="{\"key\":\"a\",\"value\":1}"`+"\n"))
+
+	var rec struct {
+		Fields map[string]pair[string, int] `json:"fields"`
+	}
+	Testing.AssertNotError(t, json.Unmarshal(e.json(), &rec))
+	Testing.AssertEqual(t, "a", rec.Fields["pair"].Key)
+
+	Testing.AssertTrue(t, strings.HasPrefix(JSONField("ch", make(chan int), DumpOptions{}).Value.(lazyJSON).String(), "!(encoding chan int as JSON"))
+}
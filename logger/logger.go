@@ -1,8 +1,6 @@
 package Logger
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"reflect"
 	"sync"
@@ -16,33 +14,57 @@ var DEBUG bool = true
 var (
 	loggerInstance Logger
 	loggeronce     sync.Once
-	loggerlogonce  sync.Once
+	loggerMutex    sync.RWMutex
 )
 
+// Initializes instance and makes it the default logger, only the first call has any effect
 func Create(instance Logger) {
 	loggeronce.Do(func() {
+		loggerMutex.Lock()
 		loggerInstance = instance
-		loggerInstance.init()
+		loggerMutex.Unlock()
+		instance.init()
 	})
 }
 
+// Makes an already initialized logger (e.g. from NewFileLogger) the default logger
+func SetLoggerInstance(instance Logger) {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	loggerInstance = instance
+}
+
+// Returns the default logger, a NullLoggerImpl until Create or SetLoggerInstance is called
 func LoggerInstance() Logger {
+	loggerMutex.RLock()
+	defer loggerMutex.RUnlock()
+	if loggerInstance == nil {
+		return &NullLoggerImpl{}
+	}
 	return loggerInstance
 }
 
+func bufferSize(size int32) int32 {
+	if size <= 0 {
+		return Logbuffersize
+	}
+	return size
+}
+
+// Fields tagged `log:"redact"` and the keys of DefaultRedactor are redacted, see DumpJSON for more control
 func PrintJson[T any](entity *T) string {
-	typename := reflect.TypeFor[T]().Name()
-	outputStringJson, err := json.MarshalIndent((*entity), "", "     ")
+	outputStringJson, err := DumpJSON(*entity, DumpOptions{Indent: "     ", EscapeHTML: true})
 	if err != nil {
-		return "Error parsing json data"
-	} else {
-		return typename + ":\n" + string(outputStringJson) + "\n"
+		return "Error parsing json data: " + err.Error()
 	}
+	typename := typeName(reflect.TypeFor[T]())
+	if reflect.TypeFor[T]().Kind() == reflect.Interface {
+		typename = TypeName(*entity)
+	}
+	return typename + ":\n" + outputStringJson + "\n"
 }
 
+// Deprecated: use AccessLog, this is AccessLog(nil, AccessLogOptions{LogHeaders: true})
 func ExampleLogMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		LoggerInstance().WriteDebug(fmt.Sprintf("\tRequest from: %s to: Host: %s URL: %s \tWith HEADERS: %s \tWith BODY: %s", r.RemoteAddr, r.Host, r.URL, r.Header, r.Body))
-		next.ServeHTTP(w, r)
-	})
+	return AccessLog(nil, AccessLogOptions{LogHeaders: true})(next)
 }
@@ -0,0 +1,43 @@
+// Test assertions for the entries kept by Logger.MemoryLoggerImpl, in the style of the Testing package.
+// They live in their own package so importing the logger doesn't link the testing package into every binary.
+package LoggerTest
+
+import (
+	"testing"
+
+	Logger "github.com/lbatuska/goutils/logger"
+)
+
+// Fails the test unless an entry of at least level containing substring was logged
+func AssertLogged(t *testing.T, lgr *Logger.MemoryLoggerImpl, level Logger.Level, substring string) {
+	t.Helper()
+	if matches := lgr.Query(Logger.MemoryQuery{MinLevel: level, Contains: substring, Limit: 1}); len(matches) > 0 {
+		t.Logf("✅ [%s](%s) logged: %s", level, substring, describe(&matches[0]))
+		return
+	}
+	t.Errorf("❌ [%s](%s) not logged", level, substring)
+}
+
+// Fails the test if an entry of at least level containing substring was logged
+func AssertNotLogged(t *testing.T, lgr *Logger.MemoryLoggerImpl, level Logger.Level, substring string) {
+	t.Helper()
+	if matches := lgr.Query(Logger.MemoryQuery{MinLevel: level, Contains: substring, Limit: 1}); len(matches) > 0 {
+		t.Errorf("❌ [%s](%s) logged: %s", level, substring, describe(&matches[0]))
+		return
+	}
+	t.Logf("✅ [%s](%s) not logged", level, substring)
+}
+
+func describe(e *Logger.Entry) string {
+	text := e.Message
+	if e.Err != nil {
+		if text != "" {
+			text += ": "
+		}
+		text += "Error: " + e.Err.Error()
+	}
+	if e.RequestID != "" {
+		text = e.RequestID + " : " + text
+	}
+	return e.Level.String() + " " + text
+}
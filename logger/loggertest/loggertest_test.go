@@ -0,0 +1,21 @@
+package LoggerTest
+
+import (
+	"errors"
+	"testing"
+
+	Logger "github.com/lbatuska/goutils/logger"
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_assertLogged(t *testing.T) {
+	lgr := Logger.NewMemoryLogger(10)
+	lgr.WriteRequest("second request", "a")
+	lgr.WriteErr(errors.New("other failure"))
+
+	AssertLogged(t, lgr, Logger.LevelError, "other failure")
+	AssertLogged(t, lgr, Logger.LevelInfo, "second request")
+	AssertNotLogged(t, lgr, Logger.LevelError, "second request")
+	AssertNotLogged(t, lgr, Logger.LevelDebug, "never written")
+	Testing.AssertEqual(t, "error Error: other failure", describe(&lgr.Entries()[1]))
+}
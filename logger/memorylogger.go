@@ -0,0 +1,143 @@
+package Logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultMemoryLoggerSize = 1000
+
+// Filters the entries of a MemoryLogger, the zero value matches everything
+type MemoryQuery struct {
+	MinLevel  Level
+	RequestID string
+	Contains  string // substring of the message or the error
+	Limit     int    // only the newest Limit matches are returned, 0 means no limit
+}
+
+// Returns a logger that keeps the last size entries (1000 if size <= 0), it doesn't need to be started
+func NewMemoryLogger(size int) *MemoryLoggerImpl {
+	lgr := &MemoryLoggerImpl{}
+	lgr.setup(size)
+	return lgr
+}
+
+func (lgr *MemoryLoggerImpl) init() {
+	lgr.setup(defaultMemoryLoggerSize)
+}
+
+func (lgr *MemoryLoggerImpl) setup(size int) {
+	if size <= 0 {
+		size = defaultMemoryLoggerSize
+	}
+	lgr.sink = lgr
+	lgr.entries = make([]Entry, size)
+}
+
+func (logger *MemoryLoggerImpl) StartLogger() {}
+
+func (logger *MemoryLoggerImpl) StopLogger() {}
+
+func (logger *MemoryLoggerImpl) Flush(ctx context.Context) error { return nil }
+
+func (logger *MemoryLoggerImpl) Close(ctx context.Context) error { return nil }
+
+func (logger *MemoryLoggerImpl) log(e *Entry) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	logger.entries[logger.next] = *e
+	logger.next++
+	if logger.next == len(logger.entries) {
+		logger.next = 0
+		logger.full = true
+	}
+}
+
+// Forgets every entry
+func (logger *MemoryLoggerImpl) Reset() {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	clear(logger.entries)
+	logger.next = 0
+	logger.full = false
+}
+
+// The kept entries, oldest first
+func (logger *MemoryLoggerImpl) Entries() []Entry {
+	return logger.Query(MemoryQuery{})
+}
+
+// The kept entries matching q, oldest first
+func (logger *MemoryLoggerImpl) Query(q MemoryQuery) []Entry {
+	logger.mutex.RLock()
+	defer logger.mutex.RUnlock()
+	var matches []Entry
+	// Walk from the newest so Limit keeps the newest ones
+	count := logger.next
+	if logger.full {
+		count = len(logger.entries)
+	}
+	for i := 0; i < count && (q.Limit <= 0 || len(matches) < q.Limit); i++ {
+		e := &logger.entries[(logger.next-1-i+len(logger.entries))%len(logger.entries)]
+		if q.matches(e) {
+			matches = append(matches, *e)
+		}
+	}
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	return matches
+}
+
+func (q *MemoryQuery) matches(e *Entry) bool {
+	if e.Level < q.MinLevel {
+		return false
+	}
+	if q.RequestID != "" && e.RequestID != q.RequestID {
+		return false
+	}
+	return q.Contains == "" || strings.Contains(e.text(), q.Contains)
+}
+
+// Serves the kept entries as text in the format documented on Logger, or as JSON with format=json.
+// The query parameters level, request_id, contains and limit filter them like MemoryQuery.
+func (logger *MemoryLoggerImpl) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	var q MemoryQuery
+	var err error
+	if level := params.Get("level"); level != "" {
+		if q.MinLevel, err = ParseLevel(level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := params.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit %q", limit), http.StatusBadRequest)
+			return
+		}
+	}
+	q.RequestID = params.Get("request_id")
+	q.Contains = params.Get("contains")
+	entries := logger.Query(q)
+
+	w.Header().Set("Cache-Control", "no-store")
+	if params.Get("format") == "json" {
+		records := make([]entryRecord, len(entries))
+		for i := range entries {
+			records[i] = entries[i].record()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(records)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for i := range entries {
+		io.WriteString(w, entries[i].format(LayoutFull, logger.timeFormat, noColors))
+	}
+}
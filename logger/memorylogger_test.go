@@ -0,0 +1,69 @@
+package Logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func memoryMessages(entries []Entry) string {
+	var messages []string
+	for _, e := range entries {
+		messages = append(messages, e.text())
+	}
+	return strings.Join(messages, ",")
+}
+
+func Test_memoryLoggerRing(t *testing.T) {
+	lgr := NewMemoryLogger(3)
+	Testing.AssertEqual(t, "", memoryMessages(lgr.Entries()))
+	for i := 1; i <= 5; i++ {
+		lgr.Write(fmt.Sprint(i))
+	}
+	Testing.AssertEqual(t, "3,4,5", memoryMessages(lgr.Entries()))
+	lgr.Reset()
+	lgr.Write("6")
+	Testing.AssertEqual(t, "6", memoryMessages(lgr.Entries()))
+}
+
+func Test_memoryLoggerQuery(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	lgr.WriteRequest("first request", "a")
+	lgr.WriteErrRequest(errors.New("failed request"), "b")
+	lgr.WriteRequest("second request", "a")
+	lgr.WriteErr(errors.New("other failure"))
+
+	Testing.AssertEqual(t, "first request,second request", memoryMessages(lgr.Query(MemoryQuery{RequestID: "a"})))
+	Testing.AssertEqual(t, "Error: failed request,Error: other failure", memoryMessages(lgr.Query(MemoryQuery{MinLevel: LevelError})))
+	Testing.AssertEqual(t, "second request,Error: other failure", memoryMessages(lgr.Query(MemoryQuery{Limit: 2})))
+	Testing.AssertEqual(t, "Error: failed request", memoryMessages(lgr.Query(MemoryQuery{Contains: "failed"})))
+}
+
+func Test_memoryLoggerHandler(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	lgr.WriteRequest("shown", "a")
+	lgr.WriteRequest("hidden", "b")
+
+	rec := httptest.NewRecorder()
+	lgr.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs?request_id=a", nil))
+	Testing.AssertTrue(t, strings.HasSuffix(rec.Body.String(), " : a : shown\n"))
+	Testing.AssertEqual(t, 1, strings.Count(rec.Body.String(), "\n"))
+
+	rec = httptest.NewRecorder()
+	lgr.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs?format=json&limit=1", nil))
+	var records []entryRecord
+	Testing.AssertNotError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+	Testing.AssertEqual(t, 1, len(records))
+	Testing.AssertEqual(t, "hidden", records[0].Message)
+	Testing.AssertEqual(t, "info", records[0].Level)
+
+	rec = httptest.NewRecorder()
+	lgr.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs?level=loud", nil))
+	Testing.AssertEqual(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,178 @@
+package Logger
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Upper bounds of the write latency histogram buckets, writes slower than the last one land in an overflow bucket
+var latencyBounds = [...]time.Duration{
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// Counters of a logger, updated with atomics so the hot path doesn't take a lock
+type metrics struct {
+	messages    [LevelError + 1]atomic.Uint64
+	bytes       atomic.Uint64
+	writes      atomic.Uint64
+	latencySum  atomic.Int64
+	latency     [len(latencyBounds) + 1]atomic.Uint64
+	mutex       sync.Mutex
+	lastErr     error
+	lastErrTime time.Time
+}
+
+// A point in time copy of the statistics of a logger
+type Stats struct {
+	Messages      map[string]uint64 `json:"messages"` // entries that reached the logger by level name
+	BytesWritten  uint64            `json:"bytes_written"`
+	Dropped       uint64            `json:"dropped"`
+	QueueDepth    int               `json:"queue_depth"` // entries waiting in the channel
+	QueueCapacity int               `json:"queue_capacity"`
+	WriteLatency  LatencyHistogram  `json:"write_latency"`
+	LastError     string            `json:"last_error,omitempty"`
+	LastErrorTime time.Time         `json:"last_error_time"`
+}
+
+// Write latencies, Buckets[i] counts the writes that took at most its UpperBound (and more than the previous one)
+type LatencyHistogram struct {
+	Count   uint64          `json:"count"`
+	Sum     time.Duration   `json:"sum_ns"`
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// UpperBound is 0 for the overflow bucket
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"le_ns"`
+	Count      uint64        `json:"count"`
+}
+
+func (m *metrics) countMessage(level Level) {
+	if level >= LevelDebug && level <= LevelError {
+		m.messages[level].Add(1)
+	}
+}
+
+// Records a write of n bytes to the output of the sink that took d, err is kept if it isn't nil
+func (m *metrics) observeWrite(n int, d time.Duration, err error) {
+	if n > 0 {
+		m.bytes.Add(uint64(n))
+	}
+	m.writes.Add(1)
+	m.latencySum.Add(int64(d))
+	bucket := len(latencyBounds)
+	for i, bound := range latencyBounds {
+		if d <= bound {
+			bucket = i
+			break
+		}
+	}
+	m.latency[bucket].Add(1)
+	if err != nil {
+		m.writeFailed(err)
+	}
+}
+
+func (m *metrics) writeFailed(err error) {
+	m.mutex.Lock()
+	m.lastErr = err
+	m.lastErrTime = time.Now()
+	m.mutex.Unlock()
+}
+
+func (m *metrics) snapshot() Stats {
+	s := Stats{
+		Messages:     make(map[string]uint64, len(m.messages)),
+		BytesWritten: m.bytes.Load(),
+		WriteLatency: LatencyHistogram{
+			Count:   m.writes.Load(),
+			Sum:     time.Duration(m.latencySum.Load()),
+			Buckets: make([]LatencyBucket, len(m.latency)),
+		},
+	}
+	for level := range m.messages {
+		s.Messages[Level(level).String()] = m.messages[level].Load()
+	}
+	for i := range m.latency {
+		if i < len(latencyBounds) {
+			s.WriteLatency.Buckets[i].UpperBound = latencyBounds[i]
+		}
+		s.WriteLatency.Buckets[i].Count = m.latency[i].Load()
+	}
+	m.mutex.Lock()
+	if m.lastErr != nil {
+		s.LastError = m.lastErr.Error()
+		s.LastErrorTime = m.lastErrTime
+	}
+	m.mutex.Unlock()
+	return s
+}
+
+// Adds the output side of other (bytes, drops, queue, latency, newer last error) to s, messages are not added
+// because the children of a MultiLogger see the same messages as their parent.
+func (s *Stats) merge(other Stats) {
+	s.BytesWritten += other.BytesWritten
+	s.Dropped += other.Dropped
+	s.QueueDepth += other.QueueDepth
+	s.QueueCapacity += other.QueueCapacity
+	s.WriteLatency.Count += other.WriteLatency.Count
+	s.WriteLatency.Sum += other.WriteLatency.Sum
+	for i := range s.WriteLatency.Buckets {
+		s.WriteLatency.Buckets[i].Count += other.WriteLatency.Buckets[i].Count
+	}
+	if other.LastErrorTime.After(s.LastErrorTime) {
+		s.LastError, s.LastErrorTime = other.LastError, other.LastErrorTime
+	}
+}
+
+// Implemented by the loggers with a channel (through logQueue)
+type queueStater interface {
+	queueStats() (depth int, capacity int, dropped uint64)
+}
+
+func (q *logQueue) queueStats() (depth int, capacity int, dropped uint64) {
+	return len(q.messages), cap(q.messages), q.dropped.Load()
+}
+
+// A snapshot of the statistics of the logger
+func (c *core) Stats() Stats {
+	s := c.metrics.snapshot()
+	if q, ok := c.sink.(queueStater); ok {
+		s.QueueDepth, s.QueueCapacity, s.Dropped = q.queueStats()
+	}
+	return s
+}
+
+// The last error writing the output of the logger, nil if there was none
+func (c *core) LastError() error {
+	c.metrics.mutex.Lock()
+	defer c.metrics.mutex.Unlock()
+	return c.metrics.lastErr
+}
+
+// Publishes the statistics of a logger through expvar: expvar.Publish("logger", StatsVar{lgr}).
+// It only needs the String method of expvar.Var, so this package doesn't register /debug/vars by importing expvar.
+type StatsVar struct {
+	Logger Logger
+}
+
+// The Stats of the logger as JSON
+func (v StatsVar) String() string {
+	b, err := json.Marshal(v.Logger.Stats())
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
@@ -0,0 +1,66 @@
+package Logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_loggerStats(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	lgr.Write("one")
+	lgr.WriteRequest("two", "uuid")
+	lgr.WriteErr(errors.New("three"))
+	lgr.WarnCtx(context.Background(), "four")
+	Testing.AssertNotError(t, lgr.Flush(context.Background()))
+
+	stats := lgr.Stats()
+	Testing.AssertEqual(t, uint64(2), stats.Messages["info"])
+	Testing.AssertEqual(t, uint64(1), stats.Messages["warn"])
+	Testing.AssertEqual(t, uint64(1), stats.Messages["error"])
+	Testing.AssertEqual(t, uint64(0), stats.Messages["debug"])
+	Testing.AssertEqual(t, 0, stats.QueueDepth)
+	Testing.AssertEqual(t, int(Logbuffersize), stats.QueueCapacity)
+	Testing.AssertTrue(t, stats.BytesWritten > 0)
+	Testing.AssertTrue(t, stats.WriteLatency.Count > 0)
+	var bucketed uint64
+	for _, b := range stats.WriteLatency.Buckets {
+		bucketed += b.Count
+	}
+	Testing.AssertEqual(t, stats.WriteLatency.Count, bucketed)
+	Testing.AssertEqual(t, "", stats.LastError)
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+}
+
+func Test_multiLoggerStats(t *testing.T) {
+	queued := &ConsoleLoggerImpl{out: io.Discard}
+	queued.init()
+	lgr := NewMultiLogger(MultiSink{Logger: queued}, MultiSink{Logger: NewMemoryLogger(10), Level: LevelError})
+	lgr.Write("waiting")
+	lgr.WriteErr(errors.New("failed"))
+
+	stats := lgr.Stats()
+	Testing.AssertEqual(t, uint64(1), stats.Messages["info"])
+	Testing.AssertEqual(t, uint64(1), stats.Messages["error"])
+	Testing.AssertEqual(t, 2, stats.QueueDepth)
+
+	var published map[string]any
+	Testing.AssertNotError(t, json.Unmarshal([]byte(StatsVar{lgr}.String()), &published))
+	Testing.AssertEqual(t, float64(2), published["queue_depth"].(float64))
+	Testing.AssertNotError(t, queued.Close(context.Background()))
+}
+
+func Test_socketLoggerStatsLastError(t *testing.T) {
+	lgr := NewSyslogLogger(SyslogOptions{Network: "unixgram", Address: "/nonexistent/socket"})
+	lgr.Write("lost")
+	Testing.AssertNotError(t, lgr.Flush(context.Background()))
+	stats := lgr.Stats()
+	Testing.AssertEqual(t, uint64(1), stats.Dropped)
+	Testing.AssertEqual(t, uint64(0), stats.BytesWritten)
+	Testing.AssertTrue(t, stats.LastError != "")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+}
@@ -0,0 +1,276 @@
+package Logger
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output format of AccessLog
+type AccessLogFormat int8
+
+const (
+	// Combined Log Format, followed by the latency and the optional headers/body as fields
+	AccessLogCombined AccessLogFormat = iota
+	// A JSON object with every recorded property
+	AccessLogJSON
+)
+
+const defaultMaxBodySize = 4 * 1024
+
+// Headers replaced by "[REDACTED]" when AccessLogOptions.RedactHeaders is nil
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Options of AccessLog, the zero value is usable
+type AccessLogOptions struct {
+	Format AccessLogFormat
+	// X-Forwarded-For is only trusted when the request comes from one of these
+	TrustedProxies []netip.Prefix
+	// Header carrying the request id, defaults to X-Request-ID. Requests without one get a random id,
+	// it is put in the request context (see RequestID) and sent back in the same response header.
+	RequestIDHeader string
+	// Log the request headers, the ones in RedactHeaders are redacted
+	LogHeaders    bool
+	RedactHeaders []string // defaults to DefaultRedactedHeaders
+	// Log the first MaxBodySize bytes of the request body (defaults to 4KiB), the handler still sees the whole body
+	CaptureBody bool
+	MaxBodySize int64
+}
+
+// Everything AccessLog records about a request
+type accessRecord struct {
+	Time      time.Time         `json:"time"`
+	RemoteIP  string            `json:"remote_ip"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Proto     string            `json:"proto"`
+	Status    int               `json:"status"`
+	Size      int64             `json:"size"`
+	Latency   time.Duration     `json:"latency_ns"`
+	Referer   string            `json:"referer,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	RequestID string            `json:"request_id"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      string            `json:"body,omitempty"`
+	Truncated bool              `json:"body_truncated,omitempty"`
+}
+
+// Records the status and size of a response
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *accessLogWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Lets http.ResponseController reach the original writer
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Returns a middleware (usable as SimpleRouter.Middleware) that logs one line per request to logger,
+// nil means the logger of the request context (see FromContext). 5xx responses are logged at Error level.
+func AccessLog(logger Logger, opts AccessLogOptions) func(http.Handler) http.Handler {
+	if opts.RequestIDHeader == "" {
+		opts.RequestIDHeader = "X-Request-ID"
+	}
+	if opts.RedactHeaders == nil {
+		opts.RedactHeaders = DefaultRedactedHeaders
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = defaultMaxBodySize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lgr := logger
+			if lgr == nil {
+				lgr = FromContext(r.Context())
+			}
+			start := time.Now()
+			rec := accessRecord{
+				Time:      lgr.now(),
+				RemoteIP:  clientIP(r, opts.TrustedProxies),
+				Method:    r.Method,
+				Path:      r.URL.RequestURI(),
+				Proto:     r.Proto,
+				Referer:   r.Referer(),
+				UserAgent: r.UserAgent(),
+				RequestID: r.Header.Get(opts.RequestIDHeader),
+			}
+			if rec.RequestID == "" {
+				rec.RequestID = RequestID(r.Context())
+			}
+			if rec.RequestID == "" {
+				rec.RequestID = newRequestID()
+			}
+			if opts.LogHeaders {
+				rec.Headers = redactHeaders(r.Header, opts.RedactHeaders)
+			}
+			if opts.CaptureBody && r.Body != nil && r.Body != http.NoBody {
+				rec.Body, rec.Truncated = captureBody(r, opts.MaxBodySize)
+			}
+
+			w.Header().Set(opts.RequestIDHeader, rec.RequestID)
+			r = r.WithContext(WithRequestID(r.Context(), rec.RequestID))
+			lw := &accessLogWriter{ResponseWriter: w}
+			next.ServeHTTP(lw, r)
+
+			rec.Latency = time.Since(start)
+			rec.Status = lw.status
+			if rec.Status == 0 {
+				rec.Status = http.StatusOK
+			}
+			rec.Size = lw.size
+			lgr.logEntry(rec.entry(opts.Format))
+		})
+	}
+}
+
+func (rec *accessRecord) entry(format AccessLogFormat) *Entry {
+	e := &Entry{Time: rec.Time, Level: LevelInfo, RequestID: rec.RequestID}
+	if rec.Status >= 500 {
+		e.Level = LevelError
+	}
+	if format == AccessLogJSON {
+		b, _ := json.Marshal(rec)
+		e.Message = string(b)
+		return e
+	}
+
+	e.Message = rec.combined()
+	e.Fields = append(e.Fields, Field{Key: "latency", Value: rec.Latency})
+	for _, name := range slices.Sorted(maps.Keys(rec.Headers)) {
+		e.Fields = append(e.Fields, Field{Key: "header." + name, Value: rec.Headers[name]})
+	}
+	if rec.Body != "" {
+		e.Fields = append(e.Fields, Field{Key: "body", Value: rec.Body})
+		if rec.Truncated {
+			e.Fields = append(e.Fields, Field{Key: "body_truncated", Value: true})
+		}
+	}
+	return e
+}
+
+// host ident authuser [date] "request" status size "referer" "user-agent"
+func (rec *accessRecord) combined() string {
+	size := "-"
+	if rec.Size > 0 {
+		size = strconv.FormatInt(rec.Size, 10)
+	}
+	return rec.RemoteIP + " - - [" + rec.Time.Format("02/Jan/2006:15:04:05 -0700") + "] " +
+		strconv.Quote(rec.Method+" "+rec.Path+" "+rec.Proto) + " " + strconv.Itoa(rec.Status) + " " + size + " " +
+		strconv.Quote(orDash(rec.Referer)) + " " + strconv.Quote(orDash(rec.UserAgent))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// The peer address, or the right-most untrusted X-Forwarded-For address if the peer is a trusted proxy
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrusted(host, trusted) {
+		return host
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !isTrusted(hop, trusted) {
+			return hop
+		}
+		host = hop
+	}
+	return host
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactHeaders(header http.Header, redact []string) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	for _, name := range redact {
+		name = http.CanonicalHeaderKey(name)
+		if _, ok := headers[name]; ok {
+			headers[name] = "[REDACTED]"
+		}
+	}
+	return headers
+}
+
+// Reads the first limit bytes of the body and puts them back in front of the rest
+func captureBody(r *http.Request, limit int64) (string, bool) {
+	captured, _ := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	truncated := int64(len(captured)) > limit
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+	if truncated {
+		captured = captured[:limit]
+	}
+	return string(captured), truncated
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,84 @@
+package Logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+	SimpleRouter "github.com/lbatuska/goutils/unstable/simplerouter"
+)
+
+func readLines(t *testing.T, lgr *FileLoggerImpl) []string {
+	t.Helper()
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+	content, err := os.ReadFile(lgr.filepath)
+	Testing.AssertNotError(t, err)
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+func Test_accessLogCombined(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	var seenBody, seenRequestID string
+	router := SimpleRouter.SimpleRouter()
+	router.PushMiddleware(AccessLog(lgr, AccessLogOptions{
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("10.0.0.0/8")},
+		LogHeaders:     true,
+		CaptureBody:    true,
+		MaxBodySize:    4,
+	}))
+	router.POST("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seenBody = string(body)
+		seenRequestID = RequestID(r.Context())
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}))
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	req, _ := http.NewRequest("POST", ts.URL+"/test", strings.NewReader("full body"))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	req.Header.Set("User-Agent", "tester")
+	res, err := http.DefaultClient.Do(req)
+	Testing.AssertNotError(t, err)
+	Testing.AssertEqual(t, http.StatusCreated, res.StatusCode)
+	Testing.AssertEqual(t, "full body", seenBody)
+	Testing.AssertEqual(t, seenRequestID, res.Header.Get("X-Request-ID"))
+
+	lines := readLines(t, lgr)
+	Testing.AssertEqual(t, 1, len(lines))
+	Testing.AssertTrue(t, strings.Contains(lines[0], " : "+seenRequestID+" : 203.0.113.7 - - ["))
+	Testing.AssertTrue(t, strings.Contains(lines[0], `] "POST /test HTTP/1.1" 201 7 "-" "tester" latency=`))
+	Testing.AssertTrue(t, strings.Contains(lines[0], `header.Authorization=[REDACTED]`))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], "body=full body_truncated=true"))
+	Testing.AssertFalse(t, strings.Contains(lines[0], "secret"))
+}
+
+func Test_accessLogJSON(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	handler := AccessLog(lgr, AccessLogOptions{Format: AccessLogJSON})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	}))
+	req := httptest.NewRequest("GET", "/fail?x=1", nil)
+	req.Header.Set("X-Request-ID", "uuid")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := readLines(t, lgr)
+	Testing.AssertEqual(t, 1, len(lines))
+	prefix := " : uuid : "
+	var rec accessRecord
+	Testing.AssertNotError(t, json.Unmarshal([]byte(lines[0][strings.Index(lines[0], prefix)+len(prefix):]), &rec))
+	Testing.AssertEqual(t, "192.0.2.1", rec.RemoteIP)
+	Testing.AssertEqual(t, "/fail?x=1", rec.Path)
+	Testing.AssertEqual(t, http.StatusInternalServerError, rec.Status)
+	Testing.AssertEqual(t, int64(len("broken\n")), rec.Size)
+}
@@ -0,0 +1,135 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type multiSink struct {
+	MultiSink
+	mutex   sync.Mutex
+	lastErr error
+}
+
+// Returns a logger that forwards every entry to the loggers of sinks, they are expected to be initialized already
+// (created by their constructor or passed to Create)
+func NewMultiLogger(sinks ...MultiSink) *MultiLogger {
+	lgr := &MultiLogger{}
+	lgr.sink = lgr
+	for _, s := range sinks {
+		lgr.sinks = append(lgr.sinks, &multiSink{MultiSink: s})
+	}
+	return lgr
+}
+
+func (lgr *MultiLogger) init() {
+	lgr.sink = lgr
+}
+
+func (logger *MultiLogger) StartLogger() {
+	logger.each(func(l Logger) error {
+		l.StartLogger()
+		return nil
+	})
+}
+
+func (logger *MultiLogger) StopLogger() {
+	logger.each(func(l Logger) error {
+		l.StopLogger()
+		return nil
+	})
+}
+
+// Flushes every sink, even if some of them fail
+func (logger *MultiLogger) Flush(ctx context.Context) error {
+	return logger.each(func(l Logger) error {
+		return l.Flush(ctx)
+	})
+}
+
+// Closes every sink, even if some of them fail
+func (logger *MultiLogger) Close(ctx context.Context) error {
+	return logger.each(func(l Logger) error {
+		return l.Close(ctx)
+	})
+}
+
+// The last error (or panic) of every sink in the order they were passed to NewMultiLogger, nil if a sink never failed
+func (logger *MultiLogger) SinkErrors() []error {
+	errs := make([]error, len(logger.sinks))
+	for i, s := range logger.sinks {
+		s.mutex.Lock()
+		errs[i] = s.lastErr
+		s.mutex.Unlock()
+	}
+	return errs
+}
+
+// Messages are counted as the MultiLogger received them, bytes, drops, queues, latencies and the last error are summed up
+// from the sinks
+func (logger *MultiLogger) Stats() Stats {
+	stats := logger.core.Stats()
+	for _, s := range logger.sinks {
+		stats.merge(s.Logger.Stats())
+	}
+	return stats
+}
+
+// Every sink gets the crash entry synchronously, one after the other
+func (logger *MultiLogger) writeCrash(ctx context.Context, e *Entry) {
+	for _, s := range logger.sinks {
+		if e.Level < s.Level {
+			continue
+		}
+		entry := *e
+		entry.Fields = slices.Clip(entry.Fields)
+		s.call(func(l Logger) error {
+			l.crash(&entry)
+			return nil
+		})
+	}
+}
+
+func (logger *MultiLogger) log(e *Entry) {
+	for _, s := range logger.sinks {
+		if e.Level < s.Level {
+			continue
+		}
+		// Every sink gets its own copy, they may redact it or still be reading it on another goroutine
+		entry := *e
+		// Fields appended by a hook of one sink must not end up in the backing array shared with the others
+		entry.Fields = slices.Clip(entry.Fields)
+		s.call(func(l Logger) error {
+			l.logEntry(&entry)
+			return nil
+		})
+	}
+}
+
+// Calls f on every sink, a failing sink doesn't keep the others from being called
+func (logger *MultiLogger) each(f func(Logger) error) error {
+	var errs []error
+	for _, s := range logger.sinks {
+		if err := s.call(f); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *multiSink) call(f func(Logger) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sink panicked: %v", r)
+		}
+		if err != nil {
+			s.mutex.Lock()
+			s.lastErr = err
+			s.mutex.Unlock()
+		}
+	}()
+	return f(s.Logger)
+}
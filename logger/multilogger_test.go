@@ -0,0 +1,55 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+// The entry in the format documented on Logger
+func (e *Entry) line() string {
+	return e.format(LayoutFull, "", noColors)
+}
+
+type panickingLogger struct {
+	NullLoggerImpl
+}
+
+func (logger *panickingLogger) logEntry(e *Entry) {
+	panic("broken sink")
+}
+
+func Test_multiLogger(t *testing.T) {
+	all := newTestFileLogger(t, FsyncNever)
+	errorsOnly := newTestFileLogger(t, FsyncNever)
+	lgr := NewMultiLogger(
+		MultiSink{Logger: &panickingLogger{}, Level: LevelDebug},
+		MultiSink{Logger: all, Level: LevelDebug},
+		MultiSink{Logger: errorsOnly, Level: LevelError},
+	)
+
+	lgr.WriteDebug("debug")
+	lgr.WriteRequest("info", "uuid")
+	Testing.AssertEqual(t, 1, lgr.WriteErrMsgRequest(errors.New("some error"), "failed", "uuid"))
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	content, _ := os.ReadFile(all.filepath)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	Testing.AssertEqual(t, 3, len(lines))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : debug"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], " : uuid : info"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[2], " : uuid failed: Error: some error"))
+
+	content, _ = os.ReadFile(errorsOnly.filepath)
+	Testing.AssertTrue(t, strings.HasSuffix(string(content), " : uuid failed: Error: some error\n"))
+	Testing.AssertEqual(t, 1, strings.Count(string(content), "\n"))
+
+	sinkErrors := lgr.SinkErrors()
+	Testing.AssertError(t, sinkErrors[0])
+	Testing.AssertNotError(t, sinkErrors[1])
+	Testing.AssertNotError(t, sinkErrors[2])
+}
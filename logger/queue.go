@@ -0,0 +1,267 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrLoggerClosed     = errors.New("logger is closed")
+	ErrLoggerNotStarted = errors.New("logger was not started")
+)
+
+// How often the writer goroutine of a buffered logger reports dropped messages (if there were any)
+var DropReportInterval = 10 * time.Second
+
+// Decides what Write does when the channel of a buffered logger is full
+type OverflowPolicy int8
+
+const (
+	// Wait until there is room in the channel (default)
+	OverflowBlock OverflowPolicy = iota
+	// Drop the message that is being written
+	OverflowDropNewest
+	// Drop the oldest message from the channel to make room for the new one
+	OverflowDropOldest
+	// Keep every Nth message (waiting for room) while the channel is full and drop the rest
+	OverflowSample
+)
+
+// Lifecycle of a buffered logger, see the Logger interface
+type loggerState int8
+
+const (
+	stateCreated loggerState = iota
+	stateRunning
+	stateStopped
+	stateClosed
+)
+
+// The channel shared by the buffered loggers together with its overflow handling and lifecycle
+type logQueue struct {
+	messages   chan *Entry
+	overflow   OverflowPolicy
+	sampleRate uint64
+	overflowed atomic.Uint64 // messages that found the channel full, used for sampling
+	dropped    atomic.Uint64
+	reported   uint64 // dropped messages already reported, only touched by the writer goroutine
+
+	mu       sync.RWMutex // guards state
+	state    loggerState
+	started  bool           // a writer was started (or is being run by Close)
+	senders  sync.WaitGroup // pushes in progress, the channel is only closed once they returned
+	stopping chan struct{}  // closed by stop, wakes up the pushes waiting for room
+	done     chan struct{}  // closed once the writer returned
+
+	enqueued   atomic.Uint64
+	processed  atomic.Uint64 // messages written out (or dropped from the channel) by the writer
+	progressMu sync.Mutex
+	progress   chan struct{} // closed and replaced every time processed grows
+}
+
+// Call it before the logger is started, sampleRate is only used by OverflowSample
+func (q *logQueue) SetOverflowPolicy(policy OverflowPolicy, sampleRate int) {
+	q.overflow = policy
+	if sampleRate < 1 {
+		sampleRate = 1
+	}
+	q.sampleRate = uint64(sampleRate)
+}
+
+// Number of messages dropped because the channel was full
+func (q *logQueue) DroppedMessages() uint64 {
+	return q.dropped.Load()
+}
+
+func (q *logQueue) initQueue(size int32) {
+	q.messages = make(chan *Entry, size)
+	q.stopping = make(chan struct{})
+	q.done = make(chan struct{})
+	q.progress = make(chan struct{})
+	if q.sampleRate == 0 {
+		q.sampleRate = 1
+	}
+}
+
+func (q *logQueue) push(e *Entry) {
+	q.mu.RLock()
+	if q.state >= stateStopped {
+		q.mu.RUnlock()
+		q.dropped.Add(1)
+		return
+	}
+	q.senders.Add(1)
+	q.mu.RUnlock()
+	defer q.senders.Done()
+
+	if q.overflow == OverflowBlock {
+		q.send(e)
+		return
+	}
+	select {
+	case q.messages <- e:
+		q.enqueued.Add(1)
+		return
+	default:
+	}
+
+	switch q.overflow {
+	case OverflowDropNewest:
+		q.dropped.Add(1)
+	case OverflowDropOldest:
+		for {
+			select {
+			case <-q.messages:
+				q.dropped.Add(1)
+				q.markProcessed(1)
+			default:
+			}
+			select {
+			case q.messages <- e:
+				q.enqueued.Add(1)
+				return
+			default:
+			}
+		}
+	case OverflowSample:
+		if q.overflowed.Add(1)%q.sampleRate == 0 {
+			q.send(e)
+		} else {
+			q.dropped.Add(1)
+		}
+	}
+}
+
+// Waits for room in the channel, the message is dropped if the logger is stopped in the meantime
+func (q *logQueue) send(e *Entry) {
+	select {
+	case q.messages <- e:
+		q.enqueued.Add(1)
+	case <-q.stopping:
+		q.dropped.Add(1)
+	}
+}
+
+// Called by the writer after n messages taken from the channel were written out
+func (q *logQueue) markProcessed(n int) {
+	q.processed.Add(uint64(n))
+	q.progressMu.Lock()
+	close(q.progress)
+	q.progress = make(chan struct{})
+	q.progressMu.Unlock()
+}
+
+// Starts run on a new goroutine, does nothing if the logger was already started or stopped
+func (q *logQueue) start(run func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.state != stateCreated {
+		return
+	}
+	q.state = stateRunning
+	q.started = true
+	go func() {
+		defer close(q.done)
+		run()
+	}()
+}
+
+// Closes the channel, the writer returns once it wrote out what was left in it.
+// Writes waiting for room are dropped, so stop never waits for the writer.
+func (q *logQueue) stop() {
+	q.mu.Lock()
+	if q.state >= stateStopped {
+		q.mu.Unlock()
+		return
+	}
+	q.state = stateStopped
+	close(q.stopping)
+	q.mu.Unlock()
+	q.senders.Wait()
+	close(q.messages)
+}
+
+// Waits until every message written before the call was processed by the writer
+func (q *logQueue) flush(ctx context.Context) error {
+	q.mu.RLock()
+	state, started := q.state, q.started
+	q.mu.RUnlock()
+	if state == stateClosed {
+		return ErrLoggerClosed
+	}
+	if !started {
+		return ErrLoggerNotStarted
+	}
+
+	target := q.enqueued.Load()
+	for {
+		q.progressMu.Lock()
+		progress := q.progress
+		q.progressMu.Unlock()
+		if q.processed.Load() >= target {
+			return nil
+		}
+		select {
+		case <-progress:
+		case <-q.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Stops the logger and waits for the writer to return (or ctx to expire), if there never was a writer run drains the channel.
+// release is called once, by the first Close that saw the writer finish.
+func (q *logQueue) close(ctx context.Context, run func(), release func() error) error {
+	q.stop()
+
+	q.mu.Lock()
+	started := q.started
+	q.started = true
+	q.mu.Unlock()
+	if !started {
+		go func() {
+			defer close(q.done)
+			run()
+		}()
+	}
+
+	select {
+	case <-q.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	q.mu.Lock()
+	if q.state == stateClosed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.state = stateClosed
+	q.mu.Unlock()
+	return release()
+}
+
+// Returns a message about the messages dropped since the last report, false if there is nothing to report.
+// now comes from the clock of the logger owning the queue.
+func (q *logQueue) droppedReport(now time.Time) (*Entry, bool) {
+	dropped := q.dropped.Load()
+	if dropped == q.reported {
+		return nil, false
+	}
+	n := dropped - q.reported
+	q.reported = dropped
+	e := messageEntry(LevelInfo, fmt.Sprintf("%d messages dropped", n))
+	e.Time = now
+	return e, true
+}
+
+// The entry has no time yet, logEntry stamps it
+func messageEntry(level Level, message string) *Entry {
+	return &Entry{Level: level, Message: message}
+}
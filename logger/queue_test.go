@@ -0,0 +1,100 @@
+package Logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func fillQueue(policy OverflowPolicy, sampleRate int) *logQueue {
+	q := &logQueue{}
+	q.SetOverflowPolicy(policy, sampleRate)
+	q.initQueue(2)
+	for _, msg := range []string{"1", "2", "3", "4", "5", "6"} {
+		q.push(messageEntry(LevelInfo, msg))
+	}
+	return q
+}
+
+func queuedMessages(q *logQueue) (messages []string) {
+	for len(q.messages) > 0 {
+		msg := <-q.messages
+		messages = append(messages, msg.Message)
+	}
+	return messages
+}
+
+func Test_overflowDropNewest(t *testing.T) {
+	q := fillQueue(OverflowDropNewest, 0)
+	Testing.AssertEqual(t, uint64(4), q.DroppedMessages())
+	Testing.AssertEqual(t, "1,2", strings.Join(queuedMessages(q), ","))
+}
+
+func Test_overflowDropOldest(t *testing.T) {
+	q := fillQueue(OverflowDropOldest, 0)
+	Testing.AssertEqual(t, uint64(4), q.DroppedMessages())
+	Testing.AssertEqual(t, "5,6", strings.Join(queuedMessages(q), ","))
+}
+
+func Test_overflowSample(t *testing.T) {
+	q := &logQueue{}
+	q.SetOverflowPolicy(OverflowSample, 2)
+	q.initQueue(2)
+	q.push(messageEntry(LevelInfo, "1"))
+	q.push(messageEntry(LevelInfo, "2"))
+	q.push(messageEntry(LevelInfo, "3"))
+	Testing.AssertEqual(t, uint64(1), q.DroppedMessages())
+	done := make(chan struct{})
+	go func() {
+		// The 2nd overflowing message is kept and waits for room
+		q.push(messageEntry(LevelInfo, "4"))
+		close(done)
+	}()
+	<-q.messages
+	<-done
+	Testing.AssertEqual(t, uint64(1), q.DroppedMessages())
+	Testing.AssertEqual(t, "2,4", strings.Join(queuedMessages(q), ","))
+}
+
+func Test_droppedReport(t *testing.T) {
+	q := fillQueue(OverflowDropNewest, 0)
+	report, ok := q.droppedReport(time.Now())
+	Testing.AssertTrue(t, ok)
+	Testing.AssertEqual(t, "4 messages dropped", report.Message)
+	_, ok = q.droppedReport(time.Now())
+	Testing.AssertFalse(t, ok)
+}
+
+func Test_stopWithBlockedWriter(t *testing.T) {
+	q := &logQueue{}
+	q.initQueue(1)
+	release := make(chan struct{})
+	q.start(func() {
+		<-release
+		for range q.messages {
+			q.markProcessed(1)
+		}
+	})
+	q.push(messageEntry(LevelInfo, "1"))
+	blocked := make(chan struct{})
+	go func() {
+		q.push(messageEntry(LevelInfo, "2"))
+		close(blocked)
+	}()
+	// Gives the push a moment to block on the full channel, the outcome is the same if it did not get there yet
+	time.Sleep(10 * time.Millisecond)
+
+	// Close has to give up when ctx expires even though the writer is stuck
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	Testing.AssertEqual(t, context.DeadlineExceeded, q.close(ctx, nil, func() error { return nil }))
+	<-blocked
+	Testing.AssertEqual(t, uint64(1), q.DroppedMessages())
+
+	close(release)
+	Testing.AssertNotError(t, q.close(context.Background(), nil, func() error { return nil }))
+	Testing.AssertEqual(t, uint64(1), q.processed.Load())
+}
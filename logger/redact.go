@@ -0,0 +1,312 @@
+package Logger
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"unsafe"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	EmailPattern       = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	CardNumberPattern  = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+	BearerTokenPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`)
+)
+
+// Used by PrintJson, loggers only redact after SetRedactor
+var DefaultRedactor = NewRedactor(
+	[]string{"authorization", "proxy-authorization", "cookie", "set-cookie", "password", "passwd", "secret", "token", "api_key", "apikey", "x-api-key"},
+	EmailPattern, CardNumberPattern, BearerTokenPattern,
+)
+
+// Removes sensitive data from entries before any sink sees them:
+//   - fields (and JSON object keys) named like one of the keys (case insensitive, for dotted keys the last part counts,
+//     a key also matches after an underscore so token covers access_token) are replaced
+//   - key=value and key: value pairs with one of the keys are replaced in messages and errors, the key may follow any
+//     character other than a letter or digit (access_token=x and csrf_token=y are replaced for token, mytoken=z isn't)
+//   - matches of the patterns are replaced in messages, errors and string fields
+//   - struct fields tagged `log:"redact"` are replaced by PrintJson and Redactor.Value
+type Redactor struct {
+	keys     map[string]struct{}
+	keyValue *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+func NewRedactor(keys []string, patterns ...*regexp.Regexp) *Redactor {
+	r := &Redactor{keys: make(map[string]struct{}, len(keys)), patterns: patterns}
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	if len(quoted) > 0 {
+		r.keyValue = regexp.MustCompile(`(?i)((?:^|[^A-Za-z0-9])(?:` + strings.Join(quoted, "|") + `))(["']?\s*[:=]\s*["']?)[^\s"'&,;]+`)
+	}
+	return r
+}
+
+func (r *Redactor) isKey(key string) bool {
+	if r == nil {
+		return false
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		key = key[i+1:]
+	}
+	key = strings.ToLower(key)
+	for {
+		if _, ok := r.keys[key]; ok {
+			return true
+		}
+		i := strings.IndexByte(key, '_')
+		if i < 0 {
+			return false
+		}
+		key = key[i+1:]
+	}
+}
+
+// Returns s with key=value pairs and pattern matches replaced
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+	// Patterns first, so a multi word value like "Authorization: Bearer x" is replaced as a whole
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, redacted)
+	}
+	if r.keyValue != nil {
+		s = r.keyValue.ReplaceAllString(s, "${1}${2}"+redacted)
+	}
+	return s
+}
+
+func (r *Redactor) redactEntry(e *Entry) {
+	e.Message = r.String(e.Message)
+	if e.Err != nil {
+		if text := r.String(e.Err.Error()); text != e.Err.Error() {
+			e.Err = errors.New(text)
+		}
+	}
+	if len(e.Fields) == 0 {
+		return
+	}
+	// The slice may be shared with a context (WithFields), never modify it in place
+	fields := make([]Field, len(e.Fields))
+	for i, f := range e.Fields {
+		switch v := f.Value.(type) {
+		case string:
+			f.Value = r.String(v)
+		case error:
+			f.Value = r.String(v.Error())
+		}
+		if r.isKey(f.Key) {
+			f.Value = redacted
+		}
+		fields[i] = f
+	}
+	e.Fields = fields
+}
+
+// Returns a copy of v that marshals to the same JSON except for the redacted parts (see Redactor), works on a nil Redactor too
+// (then only the `log:"redact"` tags count). Values implementing json.Marshaler or encoding.TextMarshaler are kept as they are.
+// A cycle is cut where it closes, marshaling the copy fails there like json.Marshal fails on v.
+func (r *Redactor) Value(v any) any {
+	return r.value(reflect.ValueOf(v), map[visit]struct{}{})
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// A pointer, map or slice on the path from the root to the value being walked, meeting it again below itself is a cycle
+type visit struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+	len int // slices sharing the start of their array are different values if their lengths differ
+}
+
+// Adds v to path if it's a non nil pointer, map or slice, leave has to be called once its elements were walked.
+// ok is false if v already is on the path.
+func enter(path map[visit]struct{}, v reflect.Value) (leave func(), ok bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return func() {}, true
+		}
+	default:
+		return func() {}, true
+	}
+	key := visit{ptr: v.UnsafePointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	if _, seen := path[key]; seen {
+		return nil, false
+	}
+	path[key] = struct{}{}
+	return func() { delete(path, key) }, true
+}
+
+// The error json.Marshal returns for a cycle through v
+func cycleError(v reflect.Value) error {
+	return &json.UnsupportedValueError{Value: v, Str: "encountered a cycle via " + v.Type().String()}
+}
+
+// Stands in for the value that closed a cycle
+type cycleValue struct {
+	v reflect.Value
+}
+
+func (c cycleValue) MarshalJSON() ([]byte, error) {
+	return nil, cycleError(c.v)
+}
+
+func (r *Redactor) value(v reflect.Value, path map[visit]struct{}) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+	leave, ok := enter(path, v)
+	if !ok {
+		return cycleValue{v}
+	}
+	defer leave()
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return r.value(v.Elem(), path)
+	case reflect.Struct:
+		obj := redactedObject{}
+		r.appendStruct(&obj, v, path)
+		return obj
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if r.isKey(key) {
+				m[key] = redacted
+			} else {
+				m[key] = r.value(iter.Value(), path)
+			}
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return v.Interface()
+		}
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = r.value(v.Index(i), path)
+		}
+		return s
+	case reflect.String:
+		return r.String(v.String())
+	default:
+		return v.Interface()
+	}
+}
+
+func (r *Redactor) appendStruct(obj *redactedObject, v reflect.Value, path map[visit]struct{}) {
+	r.structFields(v, func(name string, fv reflect.Value, redact bool) bool {
+		if redact {
+			*obj = append(*obj, redactedField{name, redacted})
+		} else {
+			*obj = append(*obj, redactedField{name, r.value(fv, path)})
+		}
+		return true
+	})
+}
+
+// Calls yield with the fields of struct v that encoding/json would output, redact is set for the ones to redact.
+// Stops early if yield returns false.
+func (r *Redactor) structFields(v reflect.Value, yield func(name string, fv reflect.Value, redact bool) bool) bool {
+	return r.embeddedFields(v, yield, map[reflect.Type]bool{})
+}
+
+// structFields for v embedded in the struct types of outer, a type embedding itself is skipped like encoding/json does
+func (r *Redactor) embeddedFields(v reflect.Value, yield func(name string, fv reflect.Value, redact bool) bool, outer map[reflect.Type]bool) bool {
+	t := v.Type()
+	outer[t] = true
+	defer delete(outer, t)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && name == "" {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if outer[fv.Type()] {
+					continue
+				}
+				if !r.embeddedFields(fv, yield, outer) {
+					return false
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() && fv.Kind() != reflect.Struct {
+			continue
+		}
+		if !yield(name, fv, sf.Tag.Get("log") == "redact" || r.isKey(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+type redactedField struct {
+	key   string
+	value any
+}
+
+// A JSON object that keeps the field order of the struct it was made from
+type redactedObject []redactedField
+
+func (o redactedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package Logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+	Pin      int    `json:"pin" log:"redact"`
+	Note     string `json:"note,omitempty"`
+	Extra    map[string]string
+}
+
+type node struct {
+	Name string `json:"name"`
+	Next *node  `json:"next,omitempty"`
+}
+
+// Embeds itself, encoding/json only outputs the fields of the outer one
+type chain struct {
+	*chain
+	Name string `json:"name"`
+}
+
+func Test_printJsonRedaction(t *testing.T) {
+	c := credentials{User: "john", Password: "hunter2", Pin: 1234, Extra: map[string]string{"Token": "abc"}}
+	Testing.AssertEqual(t, "credentials:\n{\n     \"user\": \"john\",\n     \"password\": \"[REDACTED]\",\n     \"pin\": \"[REDACTED]\",\n     \"Extra\": {\n          \"Token\": \"[REDACTED]\"\n     }\n}\n", PrintJson(&c))
+}
+
+func Test_loggerRedaction(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	lgr.SetRedactor(DefaultRedactor)
+	ctx := WithFields(context.Background(), F("header.Authorization", "Bearer abc"), F("contact", "john@example.com"))
+	lgr.InfoCtx(ctx, "login password=hunter2 card 4111 1111 1111 1111")
+	lgr.WriteErr(errors.New("request with Authorization: Bearer abc.def failed"))
+
+	lines := readLines(t, lgr)
+	Testing.AssertEqual(t, 2, len(lines))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : login password=[REDACTED] card [REDACTED] header.Authorization=[REDACTED] contact=[REDACTED]"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], " : Error: request with Authorization: [REDACTED] failed"))
+}
+
+func Test_redactorKeyBoundaries(t *testing.T) {
+	r := NewRedactor([]string{"token"})
+	Testing.AssertEqual(t, "GET /cb?access_token=[REDACTED] csrf_token=[REDACTED] token=[REDACTED]", r.String("GET /cb?access_token=abc123 csrf_token=zzz token=yyy"))
+	Testing.AssertEqual(t, "/cb?a=1&Access_Token=[REDACTED]&x-token: [REDACTED] mytoken=keep tokens=keep", r.String("/cb?a=1&Access_Token=abc&x-token: def mytoken=keep tokens=keep"))
+
+	out, err := json.Marshal(r.Value(map[string]string{"access_token": "a", "csrf_token": "b", "token": "c", "mytoken": "d"}))
+	Testing.AssertNotError(t, err)
+	Testing.AssertEqual(t, `{"access_token":"[REDACTED]","csrf_token":"[REDACTED]","mytoken":"d","token":"[REDACTED]"}`, string(out))
+}
+
+func Test_redactorCycle(t *testing.T) {
+	n := &node{Name: "a"}
+	n.Next = n
+	_, err := json.Marshal(NewRedactor(nil).Value(n))
+	var unsupported *json.UnsupportedValueError
+	Testing.AssertTrue(t, errors.As(err, &unsupported))
+	Testing.AssertEqual(t, "json: unsupported value: encountered a cycle via *Logger.node", unsupported.Error())
+
+	list := []any{nil}
+	list[0] = list
+	_, err = json.Marshal(NewRedactor(nil).Value(list))
+	Testing.AssertTrue(t, errors.As(err, &unsupported))
+
+	// The same value twice side by side isn't a cycle
+	shared := &node{Name: "b"}
+	out, err := json.Marshal(NewRedactor(nil).Value([]*node{shared, {Name: "c", Next: shared}}))
+	Testing.AssertNotError(t, err)
+	Testing.AssertEqual(t, `[{"name":"b"},{"name":"c","next":{"name":"b"}}]`, string(out))
+
+	c := &chain{Name: "outer"}
+	c.chain = c
+	out, err = json.Marshal(NewRedactor(nil).Value(c))
+	Testing.AssertNotError(t, err)
+	Testing.AssertEqual(t, `{"name":"outer"}`, string(out))
+	want, _ := json.Marshal(c)
+	Testing.AssertEqual(t, string(want), string(out))
+}
@@ -0,0 +1,78 @@
+package Logger
+
+import (
+	"context"
+
+	Type "github.com/lbatuska/goutils/type"
+)
+
+// Logs the error of an Err result like WriteErrMsgRequest (message and uuid may be empty) and returns res unchanged,
+// so it can wrap a Result in a chain:
+//
+//	user := Logger.LogResult(lgr, findUser(id), "find user", "")
+func LogResult[T any](logger Logger, res Type.Result[T], message string, uuid string) Type.Result[T] {
+	if logger == nil {
+		logger = LoggerInstance()
+	}
+	if res.IsErr() {
+		logger.emit(LevelError, message, res.UnwrapErr(), uuid)
+	}
+	return res
+}
+
+// Like LogResult with the request id, trace ids and fields of ctx, a nil logger means FromContext(ctx)
+func LogResultCtx[T any](ctx context.Context, logger Logger, res Type.Result[T], message string) Type.Result[T] {
+	if logger == nil {
+		logger = FromContext(ctx)
+	}
+	if res.IsErr() {
+		logger.emitCtx(ctx, LevelError, message, res.UnwrapErr())
+	}
+	return res
+}
+
+// Logs the error of an Err result like WriteErr and returns 1, returns 0 for Ok
+func WriteResult[T any](logger Logger, res Type.Result[T]) (errnum int) {
+	if logger == nil {
+		logger = LoggerInstance()
+	}
+	if res.IsErr() {
+		logger.emit(LevelError, "", res.UnwrapErr(), "")
+		errnum = 1
+	}
+	return errnum
+}
+
+// Logs the error of an Err result like WriteErrMsgRequest and returns 1, returns 0 for Ok
+func WriteResultMsgRequest[T any](logger Logger, res Type.Result[T], message string, uuid string) (errnum int) {
+	if logger == nil {
+		logger = LoggerInstance()
+	}
+	if res.IsErr() {
+		logger.emit(LevelError, message, res.UnwrapErr(), uuid)
+		errnum = 1
+	}
+	return errnum
+}
+
+// Logs message at level if opt is None (Debug only if DEBUG is set) and returns opt unchanged
+func LogNone[T any](logger Logger, opt Type.Optional[T], level Level, message string, uuid string) Type.Optional[T] {
+	if logger == nil {
+		logger = LoggerInstance()
+	}
+	if opt.IsNone() && (level != LevelDebug || DEBUG) {
+		logger.emit(level, message, nil, uuid)
+	}
+	return opt
+}
+
+// Like LogNone with the request id, trace ids and fields of ctx, a nil logger means FromContext(ctx)
+func LogNoneCtx[T any](ctx context.Context, logger Logger, opt Type.Optional[T], level Level, message string) Type.Optional[T] {
+	if logger == nil {
+		logger = FromContext(ctx)
+	}
+	if opt.IsNone() && (level != LevelDebug || DEBUG) {
+		logger.emitCtx(ctx, level, message, nil)
+	}
+	return opt
+}
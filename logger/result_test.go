@@ -0,0 +1,47 @@
+package Logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+	Type "github.com/lbatuska/goutils/type"
+)
+
+func Test_logResult(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	lgr.SetCallerCapture(true)
+
+	ok := LogResult(lgr, Type.Ok(42), "find answer", "uuid-1")
+	Testing.AssertEqual(t, 42, ok.Unwrap())
+	failed := LogResult(lgr, Type.Err[int](errors.New("not found")), "find answer", "uuid-1")
+	Testing.AssertTrue(t, failed.IsErr())
+	Testing.AssertEqual(t, 0, WriteResult(lgr, Type.Ok("x")))
+	Testing.AssertEqual(t, 1, WriteResult(lgr, Type.Err[string](errors.New("bad input"))))
+	Testing.AssertEqual(t, 1, WriteResultMsgRequest(lgr, Type.Err[string](errors.New("bad input")), "parse", "uuid-2"))
+	LogResultCtx(WithRequestID(context.Background(), "uuid-3"), lgr, Type.Err[int](errors.New("timeout")), "call")
+
+	entries := lgr.Entries()
+	Testing.AssertEqual(t, "find answer: Error: not found,Error: bad input,parse: Error: bad input,call: Error: timeout", memoryMessages(entries))
+	Testing.AssertEqual(t, "uuid-1", entries[0].RequestID)
+	Testing.AssertEqual(t, "uuid-2", entries[2].RequestID)
+	Testing.AssertEqual(t, "uuid-3", entries[3].RequestID)
+	Testing.AssertEqual(t, "result_test.go", entries[0].Caller.File)
+	Testing.AssertEqual(t, "result_test.go", entries[3].Caller.File)
+}
+
+func Test_logNone(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	some := LogNone(lgr, Type.Some("value"), LevelWarn, "no value", "")
+	Testing.AssertEqual(t, "value", some.Unwrap())
+	none := LogNone(lgr, Type.None[string](), LevelWarn, "no config override", "uuid-1")
+	Testing.AssertTrue(t, none.IsNone())
+	LogNoneCtx(context.Background(), lgr, Type.None[int](), LevelInfo, "no cache entry")
+
+	entries := lgr.Entries()
+	Testing.AssertEqual(t, "no config override,no cache entry", memoryMessages(entries))
+	Testing.AssertEqual(t, LevelWarn, entries[0].Level)
+	Testing.AssertEqual(t, "uuid-1", entries[0].RequestID)
+	Testing.AssertEqual(t, LevelInfo, entries[1].Level)
+}
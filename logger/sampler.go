@@ -0,0 +1,162 @@
+package Logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Keys kept by a Sampler before expired ones are cleaned up
+const maxSamplerKeys = 10000
+
+// Interval of a Sampler created with an interval <= 0
+const defaultSamplerInterval = time.Second
+
+// Limits how often the same message (same level, message and error text) is logged. In every Interval the first
+// First entries get through, after that only every Thereafter-th one (0 drops the rest of the interval).
+// NewSampler(1, 0, interval) deduplicates: every message is logged at most once per interval.
+// An interval <= 0 defaults to 1s.
+//
+// The first entry of a message that gets through after some were suppressed carries a suppressed=N field.
+// Messages that stop being logged are summarized instead: once their interval is over the next entry written
+// to the logger is preceded by a "repeated messages suppressed" entry with message, error and suppressed fields.
+// Suppressed messages are dropped before an Entry is built, they never reach the channel.
+type Sampler struct {
+	first      uint64
+	thereafter uint64
+	interval   time.Duration
+
+	mutex      sync.Mutex
+	counts     map[samplerKey]*sampleCount
+	lastSweep  time.Time
+	summaries  []samplerSummary // suppressed counts of forgotten messages, not reported yet
+	pending    atomic.Bool      // summaries is not empty
+	suppressed atomic.Uint64
+}
+
+type samplerSummary struct {
+	key        samplerKey
+	suppressed uint64
+}
+
+type samplerKey struct {
+	level   Level
+	message string
+	err     string
+}
+
+type sampleCount struct {
+	start      time.Time
+	n          uint64
+	suppressed uint64 // suppressed in the current interval and not reported yet
+}
+
+func NewSampler(first int, thereafter int, interval time.Duration) *Sampler {
+	if first < 0 {
+		first = 0
+	}
+	if thereafter < 0 {
+		thereafter = 0
+	}
+	if interval <= 0 {
+		interval = defaultSamplerInterval
+	}
+	return &Sampler{first: uint64(first), thereafter: uint64(thereafter), interval: interval, counts: map[samplerKey]*sampleCount{}}
+}
+
+// Total number of messages suppressed so far
+func (s *Sampler) SuppressedMessages() uint64 {
+	return s.suppressed.Load()
+}
+
+// Returns false if the message should be dropped, otherwise the number of suppressed messages to report with it
+func (s *Sampler) allow(level Level, message string, err error, now time.Time) (bool, uint64) {
+	key := samplerKey{level: level, message: message}
+	if err != nil {
+		key.err = err.Error()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	count, ok := s.counts[key]
+	if !ok {
+		if len(s.counts) >= maxSamplerKeys {
+			s.cleanup(now)
+		}
+		count = &sampleCount{start: now}
+		s.counts[key] = count
+	} else if now.Sub(count.start) >= s.interval {
+		count.start = now
+		count.n = 0
+	}
+
+	if now.Sub(s.lastSweep) >= s.interval {
+		s.lastSweep = now
+		s.sweep(now, key)
+	}
+
+	count.n++
+	if count.n <= s.first || (s.thereafter > 0 && (count.n-s.first)%s.thereafter == 0) {
+		suppressed := count.suppressed
+		count.suppressed = 0
+		return true, suppressed
+	}
+	count.suppressed++
+	s.suppressed.Add(1)
+	return false, 0
+}
+
+// Forgets the messages (other than current) whose interval is over, their suppressed counts go to the summaries
+func (s *Sampler) sweep(now time.Time, current samplerKey) {
+	for key, count := range s.counts {
+		if key != current && now.Sub(count.start) >= s.interval {
+			s.forget(key, count)
+		}
+	}
+}
+
+// Forgets the messages whose interval is over, or everything if that is not enough
+func (s *Sampler) cleanup(now time.Time) {
+	for key, count := range s.counts {
+		if now.Sub(count.start) >= s.interval {
+			s.forget(key, count)
+		}
+	}
+	if len(s.counts) >= maxSamplerKeys {
+		for key, count := range s.counts {
+			s.forget(key, count)
+		}
+	}
+}
+
+func (s *Sampler) forget(key samplerKey, count *sampleCount) {
+	if count.suppressed > 0 {
+		s.summaries = append(s.summaries, samplerSummary{key: key, suppressed: count.suppressed})
+		s.pending.Store(true)
+	}
+	delete(s.counts, key)
+}
+
+// Returns the summaries not reported yet as entries, nil if there are none
+func (s *Sampler) takeSummaries(now time.Time) []*Entry {
+	if !s.pending.Load() {
+		return nil
+	}
+	s.mutex.Lock()
+	summaries := s.summaries
+	s.summaries = nil
+	s.pending.Store(false)
+	s.mutex.Unlock()
+
+	entries := make([]*Entry, 0, len(summaries))
+	for _, summary := range summaries {
+		e := &Entry{Time: now, Level: summary.key.level, Message: "repeated messages suppressed"}
+		e.Fields = append(e.Fields, Field{Key: "message", Value: summary.key.message})
+		if summary.key.err != "" {
+			e.Fields = append(e.Fields, Field{Key: "error", Value: summary.key.err})
+		}
+		e.Fields = append(e.Fields, Field{Key: "suppressed", Value: summary.suppressed})
+		entries = append(entries, e)
+	}
+	return entries
+}
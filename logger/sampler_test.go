@@ -0,0 +1,87 @@
+package Logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_sampler(t *testing.T) {
+	s := NewSampler(2, 3, time.Second)
+	now := time.Now()
+	var allowed []int
+	for i := 1; i <= 10; i++ {
+		if ok, _ := s.allow(LevelInfo, "hot loop", nil, now); ok {
+			allowed = append(allowed, i)
+		}
+	}
+	Testing.AssertEqual(t, "[1 2 5 8]", fmt.Sprint(allowed))
+	Testing.AssertEqual(t, uint64(6), s.SuppressedMessages())
+
+	// Other levels and messages are counted separately
+	ok, _ := s.allow(LevelError, "hot loop", nil, now)
+	Testing.AssertTrue(t, ok)
+
+	// A new interval starts over and reports what was suppressed since the last entry that got through
+	ok, suppressed := s.allow(LevelInfo, "hot loop", nil, now.Add(time.Second))
+	Testing.AssertTrue(t, ok)
+	Testing.AssertEqual(t, uint64(2), suppressed)
+}
+
+func Test_samplerDefaultInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		s := NewSampler(1, 0, interval)
+		now := time.Now()
+		ok, _ := s.allow(LevelInfo, "line", nil, now)
+		Testing.AssertTrue(t, ok)
+		for i := 0; i < 4; i++ {
+			ok, _ = s.allow(LevelInfo, "line", nil, now)
+			Testing.AssertFalse(t, ok)
+		}
+		ok, _ = s.allow(LevelInfo, "line", nil, now.Add(defaultSamplerInterval-time.Nanosecond))
+		Testing.AssertFalse(t, ok)
+		ok, suppressed := s.allow(LevelInfo, "line", nil, now.Add(defaultSamplerInterval))
+		Testing.AssertTrue(t, ok)
+		Testing.AssertEqual(t, uint64(5), suppressed)
+	}
+}
+
+func Test_samplerDeduplication(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	lgr.SetSampler(NewSampler(1, 0, time.Hour))
+	for i := 0; i < 100; i++ {
+		lgr.WriteDebug("same line")
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		lgr.WriteDebug("same line")
+	})
+	Testing.AssertEqual(t, float64(0), allocs)
+
+	lines := readLines(t, lgr)
+	Testing.AssertEqual(t, 1, len(lines))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : same line"))
+	Testing.AssertEqual(t, uint64(200), lgr.sampler.SuppressedMessages())
+}
+
+func Test_samplerSummary(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	clock := NewManualClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	lgr.SetClock(clock)
+	lgr.SetSampler(NewSampler(1, 0, time.Minute))
+	for i := 0; i < 5; i++ {
+		lgr.WriteErr(errors.New("disk full"))
+	}
+
+	// The burst stopped, the next entry after the interval is preceded by its summary
+	clock.Advance(time.Minute)
+	lgr.Write("something else")
+	lines := readLines(t, lgr)
+	Testing.AssertEqual(t, 3, len(lines))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], " : repeated messages suppressed message=\"\" error=\"disk full\" suppressed=4"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[2], " : something else"))
+	Testing.AssertEqual(t, uint64(4), lgr.sampler.SuppressedMessages())
+}
@@ -1,83 +1,165 @@
 package Logger
 
-import "log/slog"
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
 
-func (lgr *SlogLoggerImpl) init() {}
+// Returns a logger that forwards to logger, nil means slog.Default() at the time of each message
+func NewSlogLogger(logger *slog.Logger) *SlogLoggerImpl {
+	lgr := &SlogLoggerImpl{logger: logger}
+	lgr.sink = lgr
+	return lgr
+}
+
+func (lgr *SlogLoggerImpl) init() {
+	lgr.sink = lgr
+}
 
 func (logger *SlogLoggerImpl) StartLogger() {}
 
 func (logger *SlogLoggerImpl) StopLogger() {}
 
-func (logger *SlogLoggerImpl) Write(message string) {
-	slog.Info(message)
+func (logger *SlogLoggerImpl) Flush(ctx context.Context) error { return nil }
+
+func (logger *SlogLoggerImpl) Close(ctx context.Context) error { return nil }
+
+func (logger *SlogLoggerImpl) log(e *Entry) {
+	lgr := logger.logger
+	if lgr == nil {
+		lgr = slog.Default()
+	}
+	message := e.Message
+	if e.Err != nil {
+		message += e.Err.Error()
+	}
+	attrs := make([]slog.Attr, 0, len(e.Fields)+1)
+	if e.RequestID != "" {
+		attrs = append(attrs, slog.String("UUID", e.RequestID))
+	}
+	for _, f := range e.Fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	if e.Caller != nil {
+		attrs = append(attrs, slog.String("caller", e.Caller.String()))
+	}
+	if e.Stack != "" {
+		attrs = append(attrs, slog.String("stack", e.Stack))
+	}
+	level := toSlogLevel(e.Level)
+	if e.Err != nil {
+		// The Write*Debug methods carrying an error always went to slog.Error
+		level = slog.LevelError
+	}
+	lgr.LogAttrs(context.Background(), level, message, attrs...)
 }
 
-func (logger *SlogLoggerImpl) WriteRequest(message string, uuid string) {
-	slog.Info(message, "UUID", uuid)
+func toSlogLevel(level Level) slog.Level {
+	switch level {
+	case LevelError:
+		return slog.LevelError
+	case LevelWarn:
+		return slog.LevelWarn
+	default:
+		// Debug messages were always logged at info level, slog drops debug by default
+		return slog.LevelInfo
+	}
 }
 
-func (logger *SlogLoggerImpl) WriteErr(err error) (errnum int) {
-	if err != nil {
-		slog.Error(err.Error())
-		errnum = 1
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return LevelError
+	case level >= slog.LevelWarn:
+		return LevelWarn
+	case level >= slog.LevelInfo:
+		return LevelInfo
+	default:
+		return LevelDebug
 	}
-	return errnum
 }
 
-func (logger *SlogLoggerImpl) WriteErrRequest(err error, uuid string) (errnum int) {
-	if err != nil {
-		slog.Error(err.Error(), "UUID", uuid)
-		errnum = 1
+// Returns a slog.Handler backed by logger, records below level are discarded (nil means slog.LevelInfo).
+// Debug records are also discarded while DEBUG is false.
+// Don't back it with a SlogLoggerImpl that forwards to the same slog logger, that would loop forever.
+func NewSlogHandler(logger Logger, level slog.Leveler) *SlogHandler {
+	if level == nil {
+		level = slog.LevelInfo
 	}
-	return errnum
+	return &SlogHandler{logger: logger, level: level}
 }
 
-func (logger *SlogLoggerImpl) WriteErrMsgRequest(err error, message string, uuid string) (errnum int) {
-	if err != nil {
-		slog.Error(message+err.Error(), "UUID", uuid)
-		errnum = 1
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < slog.LevelInfo && !DEBUG {
+		return false
 	}
-	return errnum
+	return level >= h.level.Level()
 }
 
-func (logger *SlogLoggerImpl) WriteDebug(message string) {
-	if DEBUG {
-		logger.Write(message)
+// Attributes named UUID or request_id become the request id of the entry, the rest become fields.
+// The caller of the record is kept if the logger captures callers.
+// The request id, trace ids and fields of ctx are picked up like in the *Ctx methods.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	e := &Entry{Time: r.Time, Level: fromSlogLevel(r.Level), Message: r.Message}
+	if h.logger.capturesCaller() {
+		e.Caller = callerAtPC(r.PC)
+	}
+	e.Fields = make([]Field, 0, len(h.attrs)+r.NumAttrs())
+	for _, f := range h.attrs {
+		e.addField(f)
 	}
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(h.group, a, e.addField)
+		return true
+	})
+	e.addContext(ctx)
+	h.logger.logEntry(e)
+	return nil
 }
 
-func (logger *SlogLoggerImpl) WriteRequestDebug(message string, uuid string) {
-	if DEBUG {
-		logger.WriteRequest(message, uuid)
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = append([]Field{}, h.attrs...)
+	for _, a := range attrs {
+		appendAttr(h.group, a, func(f Field) {
+			handler.attrs = append(handler.attrs, f)
+		})
 	}
+	return &handler
 }
 
-func (logger *SlogLoggerImpl) WriteErrDebug(err error) (errnum int) {
-	if err != nil {
-		if DEBUG {
-			logger.WriteErr(err)
-		}
-		errnum = 1
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
 	}
-	return errnum
+	handler := *h
+	handler.group = h.group + name + "."
+	return &handler
 }
 
-func (logger *SlogLoggerImpl) WriteErrRequestDebug(err error, uuid string) (errnum int) {
-	if err != nil {
-		if DEBUG {
-			logger.WriteErrRequest(err, uuid)
+// Flattens groups into dotted keys
+func appendAttr(prefix string, a slog.Attr, add func(Field)) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
 		}
-		errnum = 1
+		for _, ga := range a.Value.Group() {
+			appendAttr(prefix, ga, add)
+		}
+		return
 	}
-	return errnum
+	add(Field{Key: prefix + a.Key, Value: a.Value.Any()})
 }
 
-func (logger *SlogLoggerImpl) WriteErrMsgRequestDebug(err error, message string, uuid string) (errnum int) {
-	if err != nil {
-		if DEBUG {
-			logger.WriteErrMsgRequest(err, message, uuid)
-		}
-		errnum = 1
+func (e *Entry) addField(f Field) {
+	if f.Key == "UUID" || f.Key == "request_id" {
+		e.RequestID = fmt.Sprint(f.Value)
+		return
 	}
-	return errnum
+	e.Fields = append(e.Fields, f)
 }
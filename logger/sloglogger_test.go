@@ -0,0 +1,84 @@
+package Logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_slogLoggerInjected(t *testing.T) {
+	var buf bytes.Buffer
+	lgr := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	lgr.WriteRequest("hello", "uuid")
+	lgr.WriteErr(errors.New("some error"))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	Testing.AssertEqual(t, 2, len(lines))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], "level=INFO msg=hello UUID=uuid"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], `level=ERROR msg="some error"`))
+}
+
+func Test_slogHandler(t *testing.T) {
+	lgr := newTestFileLogger(t, FsyncNever)
+	log := slog.New(NewSlogHandler(lgr, slog.LevelInfo))
+
+	log.Debug("not logged")
+	log.With("component", "db").WithGroup("query").Info("slow query", "took", "2s", "request_id", "uuid")
+	log.Error("failed", slog.Group("user", "id", 7, "name", "John Doe"))
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	content, _ := os.ReadFile(lgr.filepath)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	Testing.AssertEqual(t, 2, len(lines))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[0], " : slow query component=db query.took=2s query.request_id=uuid"))
+	Testing.AssertTrue(t, strings.HasSuffix(lines[1], ` : failed user.id=7 user.name="John Doe"`))
+}
+
+func Test_slogHandlerCaller(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	log := slog.New(NewSlogHandler(lgr, slog.LevelInfo))
+	log.Info("without capture")
+	lgr.SetCallerCapture(true)
+	_, _, line, _ := runtime.Caller(0)
+	log.Info("with capture")
+
+	entries := lgr.Entries()
+	Testing.AssertTrue(t, entries[0].Caller == nil)
+	Testing.AssertEqual(t, "sloglogger_test.go/logger.Test_slogHandlerCaller():"+strconv.Itoa(line+1), entries[1].Caller.String())
+}
+
+func Test_slogLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	lgr := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	err := errors.New("boom")
+	writes := []struct {
+		level string
+		write func()
+	}{
+		{"INFO", func() { lgr.Write("m") }},
+		{"INFO", func() { lgr.WriteRequest("m", "uuid") }},
+		{"ERROR", func() { lgr.WriteErr(err) }},
+		{"ERROR", func() { lgr.WriteErrRequest(err, "uuid") }},
+		{"ERROR", func() { lgr.WriteErrMsgRequest(err, "m", "uuid") }},
+		{"INFO", func() { lgr.WriteDebug("m") }},
+		{"INFO", func() { lgr.WriteRequestDebug("m", "uuid") }},
+		{"ERROR", func() { lgr.WriteErrDebug(err) }},
+		{"ERROR", func() { lgr.WriteErrRequestDebug(err, "uuid") }},
+		{"ERROR", func() { lgr.WriteErrMsgRequestDebug(err, "m", "uuid") }},
+	}
+	for _, w := range writes {
+		buf.Reset()
+		w.write()
+		_, level, _ := strings.Cut(buf.String(), " level=")
+		level, _, _ = strings.Cut(level, " ")
+		Testing.AssertEqual(t, w.level, level)
+	}
+}
@@ -0,0 +1,134 @@
+package Logger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	minReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff = 30 * time.Second
+	dialTimeout         = 5 * time.Second
+)
+
+// A connection that is dialed lazily and redialed after a failed write.
+// After a failed dial no new dial is attempted until the backoff (doubling up to 30s) is over.
+type reconnectingConn struct {
+	network string
+	address string
+	conn    net.Conn
+	backoff time.Duration
+	retryAt time.Time
+}
+
+func (c *reconnectingConn) dial() error {
+	if time.Now().Before(c.retryAt) {
+		return fmt.Errorf("not reconnecting to %s %s before %s", c.network, c.address, c.retryAt.Format(time.RFC3339))
+	}
+	conn, err := net.DialTimeout(c.network, c.address, dialTimeout)
+	if err != nil {
+		c.backoff = min(max(c.backoff*2, minReconnectBackoff), maxReconnectBackoff)
+		c.retryAt = time.Now().Add(c.backoff)
+		return err
+	}
+	c.conn = conn
+	c.backoff = 0
+	return nil
+}
+
+// Writes b, on failure it reconnects and tries once more
+func (c *reconnectingConn) write(b []byte) (err error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if err = c.dial(); err != nil {
+				return err
+			}
+		}
+		if _, err = c.conn.Write(b); err == nil {
+			return nil
+		}
+		c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+func (c *reconnectingConn) close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Shared by the loggers writing to a socket (syslog, journald): the buffered channel of FileLoggerImpl and a connection
+// that is re-established when it breaks. Messages that can't be written are dropped and counted in DroppedMessages.
+type socketLogger struct {
+	core
+	logQueue
+	conn   reconnectingConn
+	format func(*Entry) []byte
+}
+
+func (logger *socketLogger) initSocket(network string, address string, size int32, format func(*Entry) []byte) {
+	logger.sink = logger
+	logger.conn = reconnectingConn{network: network, address: address}
+	logger.format = format
+	logger.initQueue(size)
+}
+
+func (logger *socketLogger) StartLogger() {
+	logger.start(logger.run)
+}
+
+func (logger *socketLogger) run() {
+	report := time.NewTicker(DropReportInterval)
+	defer report.Stop()
+	for {
+		select {
+		case msg, ok := <-logger.messages:
+			if !ok {
+				return
+			}
+			logger.send(msg)
+			logger.markProcessed(1)
+		case <-report.C:
+			if msg, ok := logger.droppedReport(logger.now()); ok {
+				logger.send(msg)
+			}
+		}
+	}
+}
+
+func (logger *socketLogger) send(e *Entry) {
+	b := logger.format(e)
+	start := time.Now()
+	err := logger.conn.write(b)
+	if err != nil {
+		logger.dropped.Add(1)
+		b = nil
+	}
+	logger.metrics.observeWrite(len(b), time.Since(start), err)
+}
+
+// Safe to call more than once
+func (logger *socketLogger) StopLogger() {
+	logger.stop()
+}
+
+// Waits until the messages written so far were sent (or dropped)
+func (logger *socketLogger) Flush(ctx context.Context) error {
+	return logger.flush(ctx)
+}
+
+// Stops the logger, waits until the queued messages are sent and closes the connection
+func (logger *socketLogger) Close(ctx context.Context) error {
+	return logger.close(ctx, logger.run, logger.conn.close)
+}
+
+func (logger *socketLogger) log(e *Entry) {
+	logger.push(e)
+}
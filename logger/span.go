@@ -0,0 +1,98 @@
+package Logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// A timed operation started by Logger.Span, End or EndErr logs how long it took and how it went.
+// Only the first End / EndErr logs, the rest are ignored.
+type Span struct {
+	core      *core
+	name      string
+	id        string
+	parentID  string
+	requestID string
+	fields    []Field
+	start     time.Time
+	ended     atomic.Bool
+}
+
+// Starts a span, the start is logged as a Debug message
+func (c *core) Span(name string, fields ...Field) *Span {
+	s := newSpan(c, name, "", "", fields)
+	if DEBUG {
+		c.emit(LevelDebug, name+" started", nil, "", s.ids()...)
+	}
+	return s
+}
+
+// Starts a span whose messages carry uuid like the ones of WriteRequest
+func (c *core) SpanRequest(name string, uuid string, fields ...Field) *Span {
+	s := newSpan(c, name, uuid, "", fields)
+	if DEBUG {
+		c.emit(LevelDebug, name+" started", nil, uuid, s.ids()...)
+	}
+	return s
+}
+
+// Starts a span nested in s, it inherits the request id and its parent_id is the id of s
+func (s *Span) Child(name string, fields ...Field) *Span {
+	child := newSpan(s.core, name, s.requestID, s.id, fields)
+	if DEBUG {
+		s.core.emit(LevelDebug, name+" started", nil, s.requestID, child.ids()...)
+	}
+	return child
+}
+
+func newSpan(c *core, name string, uuid string, parentID string, fields []Field) *Span {
+	return &Span{core: c, name: name, id: newSpanID(), parentID: parentID, requestID: uuid, fields: fields, start: c.now()}
+}
+
+// Unique id of the span, logged as span_id
+func (s *Span) ID() string {
+	return s.id
+}
+
+// Logs the span as finished successfully at Info level with its duration
+func (s *Span) End() {
+	if s.ended.Swap(true) {
+		return
+	}
+	s.core.emit(LevelInfo, s.name+" finished", nil, s.requestID, s.result("ok")...)
+}
+
+// Like End if err is nil, otherwise logs the span as failed at Error level and returns 1 like WriteErr
+func (s *Span) EndErr(err error) (errnum int) {
+	if err == nil {
+		if !s.ended.Swap(true) {
+			s.core.emit(LevelInfo, s.name+" finished", nil, s.requestID, s.result("ok")...)
+		}
+		return 0
+	}
+	if !s.ended.Swap(true) {
+		s.core.emit(LevelError, s.name+" failed", err, s.requestID, s.result("error")...)
+	}
+	return 1
+}
+
+func (s *Span) ids() []Field {
+	fields := make([]Field, 0, len(s.fields)+4)
+	fields = append(fields, Field{Key: "span_id", Value: s.id})
+	if s.parentID != "" {
+		fields = append(fields, Field{Key: "parent_id", Value: s.parentID})
+	}
+	return append(fields, s.fields...)
+}
+
+func (s *Span) result(outcome string) []Field {
+	return append(s.ids(), Field{Key: "duration", Value: s.core.now().Sub(s.start)}, Field{Key: "outcome", Value: outcome})
+}
+
+func newSpanID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
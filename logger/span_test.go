@@ -0,0 +1,51 @@
+package Logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func spanField(e Entry, key string) any {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+func Test_span(t *testing.T) {
+	lgr := NewMemoryLogger(10)
+	lgr.SetCallerCapture(true)
+	span := lgr.SpanRequest("import", "uuid-1", F("file", "a.csv"))
+	child := span.Child("parse")
+	time.Sleep(time.Millisecond)
+	Testing.AssertEqual(t, 0, child.EndErr(nil))
+	Testing.AssertEqual(t, 1, span.EndErr(errors.New("disk full")))
+	span.End()
+
+	entries := lgr.Entries()
+	Testing.AssertEqual(t, 4, len(entries))
+	started, childStarted, childEnd, end := entries[0], entries[1], entries[2], entries[3]
+	Testing.AssertEqual(t, "import started", started.Message)
+	Testing.AssertEqual(t, LevelDebug, started.Level)
+	Testing.AssertEqual(t, "span_test.go", started.Caller.File)
+	Testing.AssertEqual(t, "parse started", childStarted.Message)
+	Testing.AssertEqual(t, "span_test.go", childStarted.Caller.File)
+
+	Testing.AssertEqual(t, "parse finished", childEnd.Message)
+	Testing.AssertEqual(t, "uuid-1", childEnd.RequestID)
+	Testing.AssertEqual(t, any(span.ID()), spanField(childEnd, "parent_id"))
+	Testing.AssertEqual(t, any("ok"), spanField(childEnd, "outcome"))
+	Testing.AssertTrue(t, spanField(childEnd, "duration").(time.Duration) >= time.Millisecond)
+
+	Testing.AssertEqual(t, "import failed: Error: disk full", end.text())
+	Testing.AssertEqual(t, LevelError, end.Level)
+	Testing.AssertEqual(t, any("error"), spanField(end, "outcome"))
+	Testing.AssertEqual(t, any("a.csv"), spanField(end, "file"))
+	Testing.AssertEqual(t, nil, spanField(end, "parent_id"))
+	Testing.AssertEqual(t, "span_test.go", end.Caller.File)
+}
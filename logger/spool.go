@@ -0,0 +1,92 @@
+package Logger
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// An append-only file of newline separated records that is read back in order.
+// The file is truncated once every record was read and acknowledged with advance.
+type diskSpool struct {
+	file   *os.File
+	offset int64 // start of the first record that wasn't acknowledged
+	size   int64
+}
+
+// Records already in the file (left over from a previous run) are read first
+func openSpool(path string) (*diskSpool, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0660)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s := &diskSpool{file: f, size: info.Size()}
+	if s.size > 0 {
+		// Terminate a record that was cut short by a crash, so the next one doesn't get glued to it
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, s.size-1); err == nil && last[0] != '\n' {
+			if _, err := f.Write([]byte{'\n'}); err != nil {
+				f.Close()
+				return nil, err
+			}
+			s.size++
+		}
+	}
+	return s, nil
+}
+
+func (s *diskSpool) empty() bool {
+	return s.offset >= s.size
+}
+
+// The records must not contain a newline, they are synced to disk before it returns
+func (s *diskSpool) append(records [][]byte) error {
+	var b []byte
+	for _, r := range records {
+		b = append(b, r...)
+		b = append(b, '\n')
+	}
+	n, err := s.file.Write(b)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Returns at most max records from the start, n is the number of bytes to pass to advance once they were handled
+func (s *diskSpool) next(max int) (records [][]byte, n int64, err error) {
+	r := bufio.NewReader(io.NewSectionReader(s.file, s.offset, s.size-s.offset))
+	for len(records) < max {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		n += int64(len(line))
+		if len(line) > 1 {
+			records = append(records, line[:len(line)-1])
+		}
+	}
+	return records, n, nil
+}
+
+func (s *diskSpool) advance(n int64) error {
+	s.offset += n
+	if s.offset < s.size {
+		return nil
+	}
+	s.offset, s.size = 0, 0
+	return s.file.Truncate(0)
+}
+
+func (s *diskSpool) close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,182 @@
+package Logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RFC 5424 timestamp, microsecond precision at most
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	// Private enterprise number of the structured data carrying the fields (the one reserved for documentation)
+	syslogFieldsID = "fields@32473"
+)
+
+// Options of NewSyslogLogger, the zero value logs to the local syslog daemon on /dev/log
+type SyslogOptions struct {
+	Network    string // udp, tcp, unix or unixgram (and the other networks of net.Dial), defaults to unixgram
+	Address    string // defaults to /dev/log
+	Facility   *int   // defaults to 1 (user), a pointer so 0 (kern) can be chosen
+	AppName    string // defaults to the name of the executable
+	Hostname   string // defaults to os.Hostname()
+	BufferSize int32  // size of the channel, defaults to Logbuffersize
+	Overflow   OverflowPolicy
+	SampleRate int // used by OverflowSample
+}
+
+// Returns a started logger sending RFC 5424 messages, stream transports (tcp, tcp4, tcp6, unix) use octet counting
+// framing (RFC 6587).
+// The connection is made with the first message.
+func NewSyslogLogger(opts SyslogOptions) *SyslogLoggerImpl {
+	lgr := &SyslogLoggerImpl{opts: opts}
+	lgr.SetOverflowPolicy(opts.Overflow, opts.SampleRate)
+	lgr.setup(bufferSize(opts.BufferSize))
+	lgr.start(lgr.run)
+	return lgr
+}
+
+func (lgr *SyslogLoggerImpl) init() {
+	lgr.setup(Logbuffersize)
+}
+
+func (lgr *SyslogLoggerImpl) setup(size int32) {
+	if lgr.opts.Network == "" {
+		lgr.opts.Network = "unixgram"
+	}
+	if lgr.opts.Address == "" {
+		lgr.opts.Address = "/dev/log"
+	}
+	lgr.facility = 1
+	if lgr.opts.Facility != nil {
+		lgr.facility = *lgr.opts.Facility
+	}
+	if lgr.opts.AppName == "" {
+		lgr.opts.AppName = filepath.Base(os.Args[0])
+	}
+	if lgr.opts.Hostname == "" {
+		lgr.opts.Hostname, _ = os.Hostname()
+	}
+	lgr.pid = strconv.Itoa(os.Getpid())
+	lgr.initSocket(lgr.opts.Network, lgr.opts.Address, size, lgr.format)
+}
+
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (lgr *SyslogLoggerImpl) format(e *Entry) []byte {
+	var b strings.Builder
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(lgr.facility*8 + syslogSeverity(e.Level)))
+	b.WriteString(">1 ")
+	b.WriteString(e.Time.Format(syslogTimeFormat))
+	b.WriteByte(' ')
+	b.WriteString(syslogHeaderField(lgr.opts.Hostname, 255))
+	b.WriteByte(' ')
+	b.WriteString(syslogHeaderField(lgr.opts.AppName, 48))
+	b.WriteByte(' ')
+	b.WriteString(lgr.pid)
+	b.WriteString(" - ")
+	lgr.writeStructuredData(&b, e)
+	b.WriteByte(' ')
+	b.WriteString(e.text())
+	if e.Stack != "" {
+		b.WriteByte('\n')
+		b.WriteString(e.Stack)
+	}
+
+	msg := b.String()
+	if isStreamNetwork(lgr.opts.Network) {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+	return []byte(msg)
+}
+
+// Whether connections on network carry a byte stream instead of separate datagrams, so messages need framing
+func isStreamNetwork(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	}
+	return false
+}
+
+func (lgr *SyslogLoggerImpl) writeStructuredData(b *strings.Builder, e *Entry) {
+	if e.RequestID == "" && len(e.Fields) == 0 && e.Caller == nil {
+		b.WriteByte('-')
+		return
+	}
+	b.WriteString("[" + syslogFieldsID)
+	if e.RequestID != "" {
+		writeSyslogParam(b, "request_id", e.RequestID)
+	}
+	for _, f := range e.Fields {
+		writeSyslogParam(b, f.Key, fmt.Sprint(f.Value))
+	}
+	if e.Caller != nil {
+		writeSyslogParam(b, "caller", e.Caller.String())
+	}
+	b.WriteByte(']')
+}
+
+func writeSyslogParam(b *strings.Builder, name string, value string) {
+	b.WriteByte(' ')
+	b.WriteString(syslogParamName(name))
+	b.WriteString(`="`)
+	for _, r := range value {
+		if r == '"' || r == '\\' || r == ']' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+}
+
+// PARAM-NAME: at most 32 printable US-ASCII characters except '=', ' ', ']' and '"'
+func syslogParamName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if b.Len() == 32 {
+			break
+		}
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// Header fields are printable US-ASCII without spaces, "-" when empty
+func syslogHeaderField(value string, limit int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if b.Len() == limit {
+			break
+		}
+		if r <= ' ' || r > '~' {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+func syslogSeverity(level Level) int {
+	switch level {
+	case LevelError:
+		return 3
+	case LevelWarn:
+		return 4
+	case LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
@@ -0,0 +1,139 @@
+package Logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	Testing "github.com/lbatuska/goutils/testing"
+)
+
+func Test_syslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	Testing.AssertNotError(t, err)
+	defer conn.Close()
+
+	lgr := NewSyslogLogger(SyslogOptions{Network: "udp", Address: conn.LocalAddr().String(), AppName: "app", Hostname: "host"})
+	lgr.WriteErrMsgRequest(errors.New("boom"), "failed", "uuid-1")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	Testing.AssertNotError(t, err)
+	msg := string(buf[:n])
+	Testing.AssertTrue(t, strings.HasPrefix(msg, "<11>1 "))
+	Testing.AssertTrue(t, strings.Contains(msg, " host app "))
+	Testing.AssertTrue(t, strings.HasSuffix(msg, ` - [fields@32473 request_id="uuid-1"] failed: Error: boom`))
+}
+
+func Test_syslogTCPReconnect(t *testing.T) {
+	// Reserve an address nothing listens on yet
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Testing.AssertNotError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	lgr := NewSyslogLogger(SyslogOptions{Network: "tcp", Address: addr, AppName: "app", Hostname: "host"})
+	lgr.Write("lost")
+	Testing.AssertNotError(t, lgr.Flush(context.Background()))
+	Testing.AssertEqual(t, uint64(1), lgr.DroppedMessages())
+	Testing.AssertError(t, lgr.LastError())
+
+	ln, err = net.Listen("tcp", addr)
+	Testing.AssertNotError(t, err)
+	defer ln.Close()
+	// Writes are dropped until the backoff is over, retry until one goes through
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		dropped := lgr.DroppedMessages()
+		lgr.Write(`quoted "value"`)
+		Testing.AssertNotError(t, lgr.Flush(context.Background()))
+		if lgr.DroppedMessages() == dropped {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("logger did not reconnect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	conn, err := ln.Accept()
+	Testing.AssertNotError(t, err)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	length, err := r.ReadString(' ')
+	Testing.AssertNotError(t, err)
+	rest, _ := r.ReadString(0)
+	Testing.AssertEqual(t, strings.TrimSuffix(length, " "), strconv.Itoa(len(rest)))
+	Testing.AssertTrue(t, strings.HasSuffix(rest, ` - - quoted "value"`))
+}
+
+func Test_syslogFacilityAndFraming(t *testing.T) {
+	kern, local0 := 0, 16
+	for _, tt := range []struct {
+		network  string
+		facility *int
+		prefix   string
+		framed   bool
+	}{
+		{"udp", nil, "<11>1 ", false},
+		{"udp4", &kern, "<3>1 ", false},
+		{"unixgram", &local0, "<131>1 ", false},
+		{"unixpacket", nil, "<11>1 ", false},
+		{"tcp", nil, "<11>1 ", true},
+		{"tcp4", &kern, "<3>1 ", true},
+		{"tcp6", nil, "<11>1 ", true},
+		{"unix", nil, "<11>1 ", true},
+	} {
+		lgr := NewSyslogLogger(SyslogOptions{Network: tt.network, Address: "unused", Facility: tt.facility, AppName: "app", Hostname: "host"})
+		msg := string(lgr.format(&Entry{Level: LevelError, Message: "failed", Time: time.Now()}))
+		Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+		Testing.AssertEqual(t, tt.framed, !strings.HasPrefix(msg, "<"))
+		if tt.framed {
+			length, rest, _ := strings.Cut(msg, " ")
+			Testing.AssertEqual(t, length, strconv.Itoa(len(rest)))
+			msg = rest
+		}
+		Testing.AssertTrue(t, strings.HasPrefix(msg, tt.prefix))
+		Testing.AssertTrue(t, strings.HasSuffix(msg, " host app "+strconv.Itoa(os.Getpid())+" - - failed"))
+	}
+}
+
+func Test_journald(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	Testing.AssertNotError(t, err)
+	defer conn.Close()
+
+	lgr := NewJournaldLogger(JournaldOptions{Socket: path, Identifier: "app"})
+	ctx := WithFields(WithRequestID(context.Background(), "uuid-1"), F("user.id", 7), F("note", "two\nlines"))
+	lgr.WarnCtx(ctx, "careful")
+	Testing.AssertNotError(t, lgr.Close(context.Background()))
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	Testing.AssertNotError(t, err)
+	msg := string(buf[:n])
+	Testing.AssertTrue(t, strings.HasPrefix(msg, "MESSAGE=careful\nPRIORITY=4\nSYSLOG_IDENTIFIER=app\nREQUEST_ID=uuid-1\n"))
+	Testing.AssertTrue(t, strings.Contains(msg, "USER_ID=7\n"))
+	size := binary.LittleEndian.AppendUint64(nil, uint64(len("two\nlines")))
+	Testing.AssertTrue(t, strings.HasSuffix(msg, "NOTE\n"+string(size)+"two\nlines\n"))
+}
+
+func Test_journalFieldName(t *testing.T) {
+	Testing.AssertEqual(t, "HTTP_STATUS", journalFieldName("http.status"))
+	Testing.AssertEqual(t, "ID", journalFieldName("_1id"))
+	Testing.AssertEqual(t, "FIELD", journalFieldName("__"))
+}
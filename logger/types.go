@@ -1,24 +1,241 @@
 package Logger
 
 import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
+// Severity of a message, sinks use it to decide how to treat it
+type Level int8
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "level(" + strconv.Itoa(int(level)) + ")"
+}
+
+// Parses the names returned by Level.String, case insensitive, "warning" is accepted as well
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// Decides when FileLoggerImpl calls Sync on its file
+type FsyncPolicy int8
+
+const (
+	// Sync after every write to the file (default)
+	FsyncAlways FsyncPolicy = iota
+	// Sync at most once per interval if anything was written since the last sync
+	FsyncInterval
+	// Sync only after writes that contain an Error level message
+	FsyncOnError
+	// Never call Sync, leave it to the OS
+	FsyncNever
+)
+
+// A single message on its way to a sink
+type Entry struct {
+	Time      time.Time
+	Level     Level
+	RequestID string
+	Message   string
+	Err       error
+	Fields    []Field
+	Caller    *Caller // only set if caller capture is on
+	Stack     string  // only set for Error entries if stack capture is on
+}
+
+// Extra data attached to an entry, text sinks print it as key=value after the message
+type Field struct {
+	Key   string
+	Value any
+}
+
+// How ConsoleLoggerImpl lays out a message
+type ConsoleLayout int8
+
+const (
+	// The format documented on Logger (default)
+	LayoutFull ConsoleLayout = iota
+	// Time of day, a three letter level and the message, for development
+	LayoutCompact
+	// One JSON object per line, never colored
+	LayoutJSON
+)
+
+// Decides whether ConsoleLoggerImpl uses ANSI colors
+type ColorMode int8
+
+const (
+	// Colors if the output is a terminal, NO_COLOR is not set and TERM isn't dumb (default)
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// Options of NewConsoleLogger, the zero value is usable
+type ConsoleLoggerOptions struct {
+	BufferSize int32 // size of the channel, defaults to Logbuffersize
+	Overflow   OverflowPolicy
+	SampleRate int       // used by OverflowSample
+	Output     io.Writer // defaults to os.Stdout
+	Layout     ConsoleLayout
+	Color      ColorMode
+	TimeFormat string // see SetTimeFormat
+}
+
+// Options of NewFileLogger, the zero value is usable
+type FileLoggerOptions struct {
+	Path          string // defaults to ./log
+	BufferSize    int32  // size of the channel, defaults to Logbuffersize
+	FsyncPolicy   FsyncPolicy
+	FsyncInterval time.Duration // used by FsyncInterval, defaults to 1s
+	Overflow      OverflowPolicy
+	SampleRate    int           // used by OverflowSample
+	Layout        ConsoleLayout // LayoutFull by default, LayoutCompact and LayoutJSON work as well
+	TimeFormat    string        // see SetTimeFormat
+}
+
 // A logger without logging functionality
-type NullLoggerImpl struct{}
+type NullLoggerImpl struct {
+	core
+}
 
-// A logger that logs to sdtout
+// A logger that logs to stdout (or ConsoleLoggerOptions.Output)
 type ConsoleLoggerImpl struct {
-	messages chan string
+	core
+	logQueue
+	out     io.Writer
+	layout  ConsoleLayout
+	color   ColorMode
+	palette *palette
+	ready   sync.Once  // guards the output and palette picked by prepare
+	mutex   sync.Mutex // serializes the writes of run and writeCrash
 }
 
 type FileLoggerImpl struct {
-	messages     chan string
-	mutex        *sync.Mutex
-	logFile      *os.File
-	filepath     string
-	initfilepath string
+	core
+	logQueue
+	mutex         *sync.Mutex
+	logFile       *os.File
+	filepath      string
+	initfilepath  string
+	fsyncPolicy   FsyncPolicy
+	fsyncInterval time.Duration
+	layout        ConsoleLayout
+}
+
+// A logger that forwards to a *slog.Logger (slog.Default() if none was given)
+type SlogLoggerImpl struct {
+	core
+	logger *slog.Logger
+}
+
+// A slog.Handler that writes records to a Logger
+type SlogHandler struct {
+	logger Logger
+	level  slog.Leveler
+	attrs  []Field
+	group  string // prefix of the keys added after WithGroup, e.g. "request."
+}
+
+// A logger sending RFC 5424 messages to a syslog daemon or collector
+type SyslogLoggerImpl struct {
+	socketLogger
+	opts     SyslogOptions
+	facility int
+	pid      string
+}
+
+// A logger writing to the native journald socket with structured fields
+type JournaldLoggerImpl struct {
+	socketLogger
+	opts JournaldOptions
+}
+
+// A logger posting batches of JSON entries to a collector, spooling them to disk while it is unreachable
+type HTTPLoggerImpl struct {
+	core
+	logQueue
+	opts    HTTPLoggerOptions
+	client  *http.Client
+	spool   *diskSpool
+	backoff time.Duration
+	retryAt time.Time
 }
 
-type SlogLoggerImpl struct{}
+// A logger keeping the last entries in memory, for tests (see the loggertest package) and debug pages
+type MemoryLoggerImpl struct {
+	core
+	mutex   sync.RWMutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// A logger appending a tamper evident, hash chained JSON trail to a file, see NewAuditLogger and VerifyAuditLog
+type AuditLoggerImpl struct {
+	FileLoggerImpl
+	prevHash string
+	seq      uint64
+}
+
+// A child of a Dispatcher, it gets its own channel and goroutine
+type DispatcherSink struct {
+	Name       string // reported by Health, defaults to the type of Logger
+	Logger     Logger
+	Level      Level
+	BufferSize int32 // size of the channel, defaults to Logbuffersize
+	Overflow   OverflowPolicy
+	SampleRate int // used by OverflowSample
+}
+
+// A logger that hands every entry to the channels of its sinks, each drained by a goroutine of its own
+type Dispatcher struct {
+	core
+	workers []*dispatchWorker
+}
+
+// A child of a MultiLogger, it only receives entries at or above Level
+type MultiSink struct {
+	Logger Logger
+	Level  Level
+}
+
+// A logger that hands every entry to any number of child loggers
+type MultiLogger struct {
+	core
+	sinks []*multiSink
+}